@@ -0,0 +1,210 @@
+package sample
+
+import (
+	"math"
+	"sort"
+)
+
+// Adjustment is a method to correct for multiple comparisons, this is,
+// to control the rate of false positives when testing a family of
+// hypotheses at the same time.
+type Adjustment int
+
+// Bonferroni and Sidak control the family-wise error rate by adjusting
+// every p-value in the same way; Holm and Hochberg also control it but
+// are more powerful step-wise procedures. Hochberg assumes the tests are
+// independent or positively dependent.
+//
+// BenjaminiHochberg and BenjaminiYekutieli control the false discovery
+// rate instead, the former assuming the tests are independent or
+// positively dependent and the latter under arbitrary dependence.
+const (
+	Bonferroni Adjustment = iota
+	Sidak
+	Holm
+	Hochberg
+	BenjaminiHochberg
+	BenjaminiYekutieli
+)
+
+var adjustmentNames = map[Adjustment]string{
+	Bonferroni:         "Bonferroni",
+	Sidak:              "Šidák",
+	Holm:               "Holm",
+	Hochberg:           "Hochberg",
+	BenjaminiHochberg:  "Benjamini–Hochberg",
+	BenjaminiYekutieli: "Benjamini–Yekutieli",
+}
+
+func (a Adjustment) String() string {
+	if name, ok := adjustmentNames[a]; ok {
+		return name
+	}
+	return "unknown adjustment"
+}
+
+// AdjustPValues returns the p-values adjusted for multiple comparisons
+// using the given method, in the same order as the input. Adjusted
+// p-values are never bigger than 1.
+//
+// If any p-value is not in the [0, 1] range, it returns
+// ErrInvalidProbability.
+//
+// If the method is not one of the Adjustment constants, it returns
+// ErrUnknownAdjustment.
+func AdjustPValues(p []float64, method Adjustment) ([]float64, error) {
+	if _, ok := adjustmentNames[method]; !ok {
+		return nil, ErrUnknownAdjustment
+	}
+	for _, v := range p {
+		if !(v >= 0.0 && v <= 1.0) {
+			return nil, ErrInvalidProbability
+		}
+	}
+
+	m := float64(len(p))
+	adjusted := make([]float64, len(p))
+
+	switch method {
+	case Bonferroni:
+		for i, v := range p {
+			adjusted[i] = math.Min(1.0, m*v)
+		}
+		return adjusted, nil
+	case Sidak:
+		for i, v := range p {
+			adjusted[i] = -math.Expm1(m * math.Log1p(-v))
+		}
+		return adjusted, nil
+	}
+
+	// the step-wise methods work on the p-values in ascending order
+	order := make([]int, len(p))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return p[order[i]] < p[order[j]]
+	})
+
+	switch method {
+	case Holm:
+		// step-down: cumulative maximum from the smallest p-value
+		cummax := 0.0
+		for rank, i := range order {
+			cummax = math.Max(cummax, (m-float64(rank))*p[i])
+			adjusted[i] = math.Min(1.0, cummax)
+		}
+	default:
+		factor := func(rank int) float64 {
+			return m - float64(rank)
+		}
+		if method == BenjaminiHochberg || method == BenjaminiYekutieli {
+			q := 1.0
+			if method == BenjaminiYekutieli {
+				q = 0.0
+				for i := 1.0; i <= m; i++ {
+					q += 1.0 / i
+				}
+			}
+			factor = func(rank int) float64 {
+				return q * m / float64(rank+1)
+			}
+		}
+
+		// step-up: cumulative minimum from the biggest p-value
+		cummin := 1.0
+		for rank := len(order) - 1; rank >= 0; rank-- {
+			i := order[rank]
+			cummin = math.Min(cummin, factor(rank)*p[i])
+			adjusted[i] = cummin
+		}
+	}
+
+	return adjusted, nil
+}
+
+// AdjustConfidence returns the confidence level each of a family of m
+// confidence intervals must have for all of them to hold
+// simultaneously with the given confidence, this is, the confidence
+// level to pass to MeanConfidenceIntervals when calculating m intervals
+// at once.
+//
+// Only the Bonferroni and Sidak methods define simultaneous
+// confidence intervals, the step-wise methods are not supported.
+//
+// The functions using the table of the Student-t distribution, like
+// MeanConfidenceIntervals, support confidence levels up to 0.999, which
+// a confidence of 0.95 exceeds for more than 50 intervals. Use
+// SimultaneousMeanConfidenceIntervals for bigger families of means.
+//
+// If m is less than 1, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the method is not Bonferroni or Sidak, it returns
+// ErrUnknownAdjustment.
+func AdjustConfidence(confidence float64, m int, method Adjustment) (float64, error) {
+	if err := checkSampleSize("AdjustConfidence", m, 1); err != nil {
+		return 0.0, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return 0.0, err
+	}
+
+	switch method {
+	case Bonferroni:
+		return 1.0 - (1.0-confidence)/float64(m), nil
+	case Sidak:
+		return math.Pow(confidence, 1.0/float64(m)), nil
+	default:
+		return 0.0, ErrUnknownAdjustment
+	}
+}
+
+// SimultaneousMeanConfidenceIntervals calculates the confidence
+// intervals of the means of several samples, so that all of them hold
+// simultaneously with the given confidence, adjusting it with
+// AdjustConfidence and the given method. The intervals are in the same
+// order as the samples.
+//
+// The critical values of the Student-t distribution are computed
+// instead of looked up in a table, like in
+// WeightedMeanConfidenceIntervals, so any number of samples is
+// supported, but the intervals are slightly narrower than the
+// conservative ones of MeanConfidenceIntervals.
+//
+// If there are no samples, or a sample has less than 2 sample points,
+// it returns ErrSampleTooSmall; otherwise it returns the same errors as
+// AdjustConfidence.
+func SimultaneousMeanConfidenceIntervals(
+	samples [][]float64,
+	confidence float64,
+	method Adjustment,
+) ([][2]float64, error) {
+	const fn = "SimultaneousMeanConfidenceIntervals"
+	if err := checkSampleSize(fn, len(samples), 1); err != nil {
+		return nil, err
+	}
+	adjusted, err := AdjustConfidence(confidence, len(samples), method)
+	if err != nil {
+		return nil, err
+	}
+
+	p := 1.0 - (1.0-adjusted)/2.0
+	intervals := make([][2]float64, len(samples))
+	for i, data := range samples {
+		var m moments
+		for _, x := range data {
+			m.add(x)
+		}
+		if err := checkSampleSize(fn, m.n, 2); err != nil {
+			return nil, err
+		}
+		margin := studentTQuantile(p, float64(m.n-1)) * math.Sqrt(m.variance()/float64(m.n))
+		intervals[i] = [2]float64{m.mean - margin, m.mean + margin}
+	}
+
+	return intervals, nil
+}
@@ -0,0 +1,251 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAdjustPValues(t *testing.T) {
+	t.Parallel()
+	input := []float64{0.01, 0.04, 0.03, 0.005}
+	for _, test := range []struct {
+		method Adjustment
+		want   []float64
+	}{
+		{
+			method: Bonferroni,
+			want:   []float64{0.04, 0.16, 0.12, 0.02},
+		}, {
+			method: Sidak,
+			want:   []float64{0.039404, 0.150653, 0.114707, 0.019850},
+		}, {
+			method: Holm,
+			want:   []float64{0.03, 0.06, 0.06, 0.02},
+		}, {
+			method: Hochberg,
+			want:   []float64{0.03, 0.04, 0.04, 0.02},
+		}, {
+			method: BenjaminiHochberg,
+			want:   []float64{0.02, 0.04, 0.04, 0.02},
+		}, {
+			method: BenjaminiYekutieli,
+			want:   []float64{0.041667, 0.083333, 0.083333, 0.041667},
+		},
+	} {
+		test := test
+		t.Run(test.method.String(), func(t *testing.T) {
+			t.Parallel()
+			got, err := AdjustPValues(input, test.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+			for i := range got {
+				if !equals(got[i], test.want[i], tolerance/100) {
+					t.Errorf("want %v, got %v", test.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestAdjustPValuesNeverAboveOne(t *testing.T) {
+	t.Parallel()
+	input := []float64{0.5, 0.9, 0.7, 1}
+	for method := range adjustmentNames {
+		got, err := AdjustPValues(input, method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, p := range got {
+			if p > 1 || p < input[i] {
+				t.Errorf("%s: adjusted p-value %f out of range [%f, 1]", method, p, input[i])
+			}
+		}
+	}
+}
+
+func TestAdjustPValuesErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		input       []float64
+		method      Adjustment
+		want        error
+	}{
+		{
+			description: "negative p-value",
+			input:       []float64{0.1, -0.1},
+			method:      Holm,
+			want:        ErrInvalidProbability,
+		}, {
+			description: "p-value above one",
+			input:       []float64{1.1},
+			method:      Bonferroni,
+			want:        ErrInvalidProbability,
+		}, {
+			description: "unknown method",
+			input:       []float64{0.1},
+			method:      Adjustment(-1),
+			want:        ErrUnknownAdjustment,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := AdjustPValues(test.input, test.method)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !errors.Is(err, test.want) {
+				t.Errorf("want %q, got %q", test.want, err)
+			}
+		})
+	}
+}
+
+func TestAdjustConfidence(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		confidence float64
+		m          int
+		method     Adjustment
+		want       float64
+	}{
+		{confidence: 0.95, m: 1, method: Bonferroni, want: 0.95},
+		{confidence: 0.95, m: 10, method: Bonferroni, want: 0.995},
+		{confidence: 0.95, m: 1, method: Sidak, want: 0.95},
+		{confidence: 0.95, m: 10, method: Sidak, want: 0.994884},
+		{confidence: 0.95, m: 100, method: Bonferroni, want: 0.9995},
+		{confidence: 0.95, m: 100, method: Sidak, want: 0.999487},
+	} {
+		got, err := AdjustConfidence(test.confidence, test.m, test.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equals(got, test.want, tolerance/100) {
+			t.Errorf("%s, m=%d: want %f, got %f", test.method, test.m, test.want, got)
+		}
+	}
+}
+
+func TestSimultaneousMeanConfidenceIntervals(t *testing.T) {
+	t.Parallel()
+	data := []float64{1.1, 0.9, 1.1, 1.3, 1.0}
+	for _, test := range []struct {
+		method Adjustment
+		m      int
+		want   [2]float64
+	}{
+		// the Student-t critical values are 2.776, 10.306 and 10.238
+		{Bonferroni, 1, [2]float64{0.8958, 1.2642}},
+		{Bonferroni, 100, [2]float64{0.3964, 1.7636}},
+		{Sidak, 100, [2]float64{0.4008, 1.7592}},
+	} {
+		samples := make([][]float64, test.m)
+		for i := range samples {
+			samples[i] = data
+		}
+		got, err := SimultaneousMeanConfidenceIntervals(samples, 0.95, test.method)
+		if err != nil {
+			t.Fatalf("%s, m=%d: %v", test.method, test.m, err)
+		}
+		if len(got) != test.m {
+			t.Fatalf("%s, m=%d: want %d intervals, got %d", test.method, test.m, test.m, len(got))
+		}
+		for i := range got {
+			if !pairEquals(got[i], test.want, tolerance) {
+				t.Fatalf("%s, m=%d: want %v, got %v", test.method, test.m, test.want, got[i])
+			}
+		}
+	}
+}
+
+func TestSimultaneousMeanConfidenceIntervalsErrors(t *testing.T) {
+	t.Parallel()
+	data := []float64{1.1, 0.9, 1.1}
+	for _, test := range []struct {
+		description string
+		samples     [][]float64
+		confidence  float64
+		method      Adjustment
+		want        error
+	}{
+		{
+			description: "no samples",
+			confidence:  0.95,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "small sample",
+			samples:     [][]float64{data, {1.0}},
+			confidence:  0.95,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "invalid confidence",
+			samples:     [][]float64{data},
+			confidence:  1,
+			want:        ErrInvalidConfidence,
+		}, {
+			description: "step-wise method",
+			samples:     [][]float64{data},
+			confidence:  0.95,
+			method:      Holm,
+			want:        ErrUnknownAdjustment,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := SimultaneousMeanConfidenceIntervals(test.samples, test.confidence, test.method)
+			if !errors.Is(err, test.want) {
+				t.Errorf("want %q, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestAdjustConfidenceErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		confidence  float64
+		m           int
+		method      Adjustment
+		want        error
+	}{
+		{
+			description: "empty family",
+			confidence:  0.95,
+			m:           0,
+			method:      Bonferroni,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "invalid confidence",
+			confidence:  1,
+			m:           3,
+			method:      Bonferroni,
+			want:        ErrInvalidConfidence,
+		}, {
+			description: "step-wise method",
+			confidence:  0.95,
+			m:           3,
+			method:      Holm,
+			want:        ErrUnknownAdjustment,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := AdjustConfidence(test.confidence, test.m, test.method)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !errors.Is(err, test.want) {
+				t.Errorf("want %q, got %q", test.want, err)
+			}
+		})
+	}
+}
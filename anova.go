@@ -0,0 +1,206 @@
+package sample
+
+// GroupSummary describes one of the groups compared by a multi-group
+// test.
+type GroupSummary struct {
+	N                 int
+	Mean              float64
+	StandardDeviation float64
+}
+
+// ANOVAResult is the outcome of a test comparing several groups, either
+// their means or their variances.
+//
+// Statistic is the F statistic for the ANOVA, F-test, Levene and
+// Brown–Forsythe tests, the H statistic for the Kruskal–Wallis test and
+// the chi-square statistic for the Bartlett test. DF1 and DF2 are the
+// numerator and denominator degrees of freedom of the F statistic; the
+// H and chi-square statistics only have DF1, DF2 is zero in that case.
+type ANOVAResult struct {
+	Statistic float64
+	DF1       float64
+	DF2       float64
+	PValue    float64
+	Groups    []GroupSummary
+}
+
+// Returns the summaries of the groups, with each group needing at least
+// 2 sample points, and checks there are at least 2 groups. Errors are
+// reported as coming from the function fn.
+func summarizeGroups(fn string, groups [][]float64) ([]GroupSummary, error) {
+	if len(groups) < 2 {
+		return nil, ErrTooFewGroups
+	}
+
+	summaries := make([]GroupSummary, len(groups))
+	for i, g := range groups {
+		if err := checkSampleSize(fn, len(g), 2); err != nil {
+			return nil, err
+		}
+		sd, _ := StandardDeviation(g)
+		mean, _ := Mean(g)
+		summaries[i] = GroupSummary{
+			N:                 len(g),
+			Mean:              mean,
+			StandardDeviation: sd,
+		}
+	}
+
+	return summaries, nil
+}
+
+// OneWayANOVA performs a one-way analysis of variance, testing the null
+// hypothesis that all the groups come from populations with the same
+// mean. It assumes the populations are Normal and have equal variances.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If all the sample points are equal, it returns ErrZeroVariance.
+func OneWayANOVA(groups [][]float64) (ANOVAResult, error) {
+	summaries, err := summarizeGroups("OneWayANOVA", groups)
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+
+	total := 0
+	grandSum := 0.0
+	for _, s := range summaries {
+		total += s.N
+		grandSum += float64(s.N) * s.Mean
+	}
+	grandMean := grandSum / float64(total)
+
+	between := 0.0
+	within := 0.0
+	var diff float64
+	for _, s := range summaries {
+		diff = s.Mean - grandMean
+		between += float64(s.N) * diff * diff
+		within += float64(s.N-1) * s.StandardDeviation * s.StandardDeviation
+	}
+	if between == 0.0 && within == 0.0 {
+		return ANOVAResult{}, ErrZeroVariance
+	}
+
+	df1 := float64(len(groups) - 1)
+	df2 := float64(total - len(groups))
+	f := (between / df1) / (within / df2)
+
+	return ANOVAResult{
+		Statistic: f,
+		DF1:       df1,
+		DF2:       df2,
+		PValue:    fSurvival(f, df1, df2),
+		Groups:    summaries,
+	}, nil
+}
+
+// WelchANOVA performs Welch's heteroscedastic one-way analysis of
+// variance, testing the null hypothesis that all the groups come from
+// populations with the same mean. Unlike OneWayANOVA, it does not
+// assume the populations have equal variances.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the standard deviation of any group is zero, it returns
+// ErrZeroVariance.
+func WelchANOVA(groups [][]float64) (ANOVAResult, error) {
+	summaries, err := summarizeGroups("WelchANOVA", groups)
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+
+	weights := make([]float64, len(summaries))
+	sumWeights := 0.0
+	weightedMean := 0.0
+	for i, s := range summaries {
+		if s.StandardDeviation == 0.0 {
+			return ANOVAResult{}, ErrZeroVariance
+		}
+		weights[i] = float64(s.N) / (s.StandardDeviation * s.StandardDeviation)
+		sumWeights += weights[i]
+		weightedMean += weights[i] * s.Mean
+	}
+	weightedMean /= sumWeights
+
+	k := float64(len(summaries))
+	a := 0.0
+	tmp := 0.0
+	var diff, frac float64
+	for i, s := range summaries {
+		diff = s.Mean - weightedMean
+		a += weights[i] * diff * diff
+		frac = 1.0 - weights[i]/sumWeights
+		tmp += frac * frac / float64(s.N-1)
+	}
+	a /= k - 1.0
+	b := 1.0 + 2.0*(k-2.0)/(k*k-1.0)*tmp
+
+	f := a / b
+	df1 := k - 1.0
+	df2 := (k*k - 1.0) / (3.0 * tmp)
+
+	return ANOVAResult{
+		Statistic: f,
+		DF1:       df1,
+		DF2:       df2,
+		PValue:    fSurvival(f, df1, df2),
+		Groups:    summaries,
+	}, nil
+}
+
+// KruskalWallis performs the Kruskal–Wallis H test, a rank-based
+// alternative to OneWayANOVA that does not assume the populations are
+// Normal. The H statistic is corrected for ties and its p-value
+// calculated using the chi-square approximation, which is inaccurate
+// for groups of less than 5 sample points.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If all the sample points are equal, it returns ErrZeroVariance.
+func KruskalWallis(groups [][]float64) (ANOVAResult, error) {
+	summaries, err := summarizeGroups("KruskalWallis", groups)
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+
+	all := []float64{}
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	r, ties := ranks(all)
+
+	n := float64(len(all))
+	correction := 1.0 - ties/(n*n*n-n)
+	if correction == 0.0 {
+		return ANOVAResult{}, ErrZeroVariance
+	}
+
+	h := 0.0
+	offset := 0
+	for _, g := range groups {
+		rankSum := sum(r[offset : offset+len(g)])
+		h += rankSum * rankSum / float64(len(g))
+		offset += len(g)
+	}
+	h = 12.0/(n*(n+1.0))*h - 3.0*(n+1.0)
+	h /= correction
+
+	df := float64(len(groups) - 1)
+
+	return ANOVAResult{
+		Statistic: h,
+		DF1:       df,
+		PValue:    chiSquareSurvival(h, df),
+		Groups:    summaries,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var anovaGroups = [][]float64{
+	{6, 8, 4, 5, 3, 4},
+	{8, 12, 9, 11, 6, 8},
+	{13, 9, 11, 8, 7, 12},
+}
+
+func checkANOVAResult(t *testing.T, got, want ANOVAResult) {
+	t.Helper()
+	if !equals(got.Statistic, want.Statistic, tolerance) {
+		t.Errorf("wrong statistic: want %f, got %f", want.Statistic, got.Statistic)
+	}
+	if !equals(got.DF1, want.DF1, tolerance) {
+		t.Errorf("wrong DF1: want %f, got %f", want.DF1, got.DF1)
+	}
+	if !equals(got.DF2, want.DF2, tolerance) {
+		t.Errorf("wrong DF2: want %f, got %f", want.DF2, got.DF2)
+	}
+	if !equals(got.PValue, want.PValue, tolerance/10) {
+		t.Errorf("wrong p-value: want %f, got %f", want.PValue, got.PValue)
+	}
+}
+
+func TestOneWayANOVA(t *testing.T) {
+	t.Parallel()
+	got, err := OneWayANOVA(anovaGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 9.26471,
+		DF1:       2,
+		DF2:       15,
+		PValue:    0.002398,
+	})
+
+	want := []GroupSummary{
+		{N: 6, Mean: 5, StandardDeviation: 1.78885},
+		{N: 6, Mean: 9, StandardDeviation: 2.19089},
+		{N: 6, Mean: 10, StandardDeviation: 2.36643},
+	}
+	if len(got.Groups) != len(want) {
+		t.Fatalf("wrong number of groups: want %d, got %d", len(want), len(got.Groups))
+	}
+	for i, w := range want {
+		g := got.Groups[i]
+		if g.N != w.N ||
+			!equals(g.Mean, w.Mean, tolerance) ||
+			!equals(g.StandardDeviation, w.StandardDeviation, tolerance) {
+			t.Errorf("group %d: want %v, got %v", i, w, g)
+		}
+	}
+}
+
+func TestWelchANOVA(t *testing.T) {
+	t.Parallel()
+	got, err := WelchANOVA(anovaGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 9.93905,
+		DF1:       2,
+		DF2:       9.85061,
+		PValue:    0.004337,
+	})
+}
+
+func TestKruskalWallis(t *testing.T) {
+	t.Parallel()
+	got, err := KruskalWallis(anovaGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 9.42068,
+		DF1:       2,
+		PValue:    0.008999,
+	})
+}
+
+func TestANOVAErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		groups      [][]float64
+		want        error
+	}{
+		{
+			description: "nil groups",
+			groups:      nil,
+			want:        ErrTooFewGroups,
+		}, {
+			description: "one group",
+			groups:      [][]float64{{1, 2, 3}},
+			want:        ErrTooFewGroups,
+		}, {
+			description: "small group",
+			groups:      [][]float64{{1, 2, 3}, {1}},
+			want:        ErrSampleTooSmall,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			for name, f := range map[string]func([][]float64) (ANOVAResult, error){
+				"OneWayANOVA":   OneWayANOVA,
+				"WelchANOVA":    WelchANOVA,
+				"KruskalWallis": KruskalWallis,
+			} {
+				_, err := f(test.groups)
+				if err == nil {
+					t.Fatalf("%s: unexpected success", name)
+				}
+				if !errors.Is(err, test.want) {
+					t.Errorf("%s: want %q, got %q", name, test.want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestANOVAZeroVariance(t *testing.T) {
+	t.Parallel()
+	groups := [][]float64{{1, 1, 1}, {1, 1}}
+	if _, err := OneWayANOVA(groups); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("OneWayANOVA: want %q, got %v", ErrZeroVariance, err)
+	}
+	if _, err := WelchANOVA(groups); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("WelchANOVA: want %q, got %v", ErrZeroVariance, err)
+	}
+	if _, err := KruskalWallis(groups); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("KruskalWallis: want %q, got %v", ErrZeroVariance, err)
+	}
+}
+
+// Without variance within the groups, the F statistic of OneWayANOVA is
+// 0/0 when the means are equal, which used to give a NaN statistic and
+// p-value, and +Inf otherwise.
+func TestOneWayANOVAZeroWithinVariance(t *testing.T) {
+	t.Parallel()
+	result, err := OneWayANOVA([][]float64{{1, 1, 1}, {2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(result.Statistic, 1) || result.PValue != 0.0 {
+		t.Errorf("want an infinite F and a zero p-value, got %+v", result)
+	}
+}
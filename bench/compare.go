@@ -0,0 +1,231 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+
+	"github.com/alcortesm/sample"
+)
+
+// Stats summarizes the values of a unit across the runs of a benchmark.
+// Interval are the confidence intervals of the mean, they are zero if
+// there are less than 2 runs.
+type Stats struct {
+	Values   []float64
+	Mean     float64
+	Interval [2]float64
+}
+
+// N returns the number of runs.
+func (s *Stats) N() int {
+	return len(s.Values)
+}
+
+// Comparison is the comparison of the values of a unit of a benchmark
+// between the old and the new runs.
+//
+// Old or New are nil when the benchmark only appears in one of the
+// runs. Delta is the relative change from the old mean to the new
+// mean, zero if any of them is missing. Significant tells if the change
+// is statistically significant at the confidence level used, its
+// p-value is PValue, which is 1 when there are not enough runs to test
+// for significance.
+type Comparison struct {
+	Pkg         string
+	Name        string
+	Unit        string
+	Old         *Stats
+	New         *Stats
+	Delta       float64
+	PValue      float64
+	Significant bool
+}
+
+type key struct {
+	pkg  string
+	name string
+	unit string
+}
+
+// Compare groups the old and new results by package, benchmark name and
+// unit, and compares the values of each group.
+//
+// The significance of the changes is tested with the Mann–Whitney U
+// test, computed as a two-group Kruskal–Wallis test, which does not
+// assume benchmark timings are Normal; at least 2 runs of each
+// benchmark are needed, but it rarely finds significant changes with
+// less than 5. The comparisons are returned grouped by package and
+// unit, in the order the packages, units and benchmarks first appear.
+//
+// If the confidence value is not in the ]0, 1[ it returns a
+// *sample.ConfidenceError.
+func Compare(old, new []Result, confidence float64) ([]Comparison, error) {
+	if !(confidence > 0.0 && confidence < 1.0) {
+		return nil, &sample.ConfidenceError{Value: confidence}
+	}
+
+	type section struct {
+		pkg  string
+		unit string
+	}
+	sections := []section{}
+	keys := map[section][]key{}
+	values := map[key]*[2][]float64{}
+
+	add := func(results []Result, side int) {
+		for _, r := range results {
+			for _, m := range r.Metrics {
+				s := section{pkg: r.Pkg, unit: m.Unit}
+				if _, ok := keys[s]; !ok {
+					sections = append(sections, s)
+					keys[s] = nil
+				}
+				k := key{pkg: r.Pkg, name: r.Name, unit: m.Unit}
+				if _, ok := values[k]; !ok {
+					values[k] = &[2][]float64{}
+					keys[s] = append(keys[s], k)
+				}
+				values[k][side] = append(values[k][side], m.Value)
+			}
+		}
+	}
+	add(old, 0)
+	add(new, 1)
+
+	comparisons := []Comparison{}
+	for _, s := range sections {
+		for _, k := range keys[s] {
+			c, err := compare(k, values[k][0], values[k][1], confidence)
+			if err != nil {
+				return nil, err
+			}
+			comparisons = append(comparisons, c)
+		}
+	}
+
+	return comparisons, nil
+}
+
+func compare(k key, old, new []float64, confidence float64) (Comparison, error) {
+	c := Comparison{
+		Pkg:    k.pkg,
+		Name:   k.name,
+		Unit:   k.unit,
+		PValue: 1.0,
+	}
+
+	var err error
+	if c.Old, err = stats(old, confidence); err != nil {
+		return Comparison{}, err
+	}
+	if c.New, err = stats(new, confidence); err != nil {
+		return Comparison{}, err
+	}
+	if c.Old == nil || c.New == nil {
+		return c, nil
+	}
+
+	switch {
+	case c.Old.Mean == c.New.Mean:
+		c.Delta = 0.0
+	case c.Old.Mean == 0.0:
+		c.Delta = math.Inf(1)
+		if c.New.Mean < 0.0 {
+			c.Delta = math.Inf(-1)
+		}
+	default:
+		c.Delta = (c.New.Mean - c.Old.Mean) / math.Abs(c.Old.Mean)
+	}
+
+	if len(old) < 2 || len(new) < 2 {
+		return c, nil
+	}
+
+	test, err := sample.KruskalWallis([][]float64{old, new})
+	if errors.Is(err, sample.ErrZeroVariance) {
+		// all the values are equal, there is no change
+		return c, nil
+	}
+	if err != nil {
+		return Comparison{}, err
+	}
+	c.PValue = test.PValue
+	c.Significant = test.PValue < 1.0-confidence
+
+	return c, nil
+}
+
+func stats(values []float64, confidence float64) (*Stats, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	s := &Stats{Values: values}
+	s.Mean, _ = sample.Mean(values)
+	if len(values) < 2 {
+		return s, nil
+	}
+
+	var err error
+	if s.Interval, err = sample.MeanConfidenceIntervals(values, confidence); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Render writes the comparisons as a table, with a section for each
+// package and unit. Each row shows the old and new means with the
+// relative half-width of their confidence intervals, and the relative
+// change between them, or "~" if the change is not statistically
+// significant, followed by the p-value and the number of runs.
+func Render(w io.Writer, comparisons []Comparison) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for i, c := range comparisons {
+		newPkg := i == 0 || c.Pkg != comparisons[i-1].Pkg
+		newUnit := i == 0 || c.Unit != comparisons[i-1].Unit
+		if newPkg || newUnit {
+			if i != 0 {
+				fmt.Fprintln(tw)
+			}
+			if newPkg && c.Pkg != "" {
+				fmt.Fprintf(tw, "pkg: %s\n", c.Pkg)
+			}
+			fmt.Fprintf(tw, "name\told %s\tnew %s\tdelta\n", c.Unit, c.Unit)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			c.Name, formatStats(c.Old), formatStats(c.New), formatDelta(c))
+	}
+
+	return tw.Flush()
+}
+
+func formatStats(s *Stats) string {
+	if s == nil {
+		return ""
+	}
+	if s.N() < 2 {
+		return fmt.Sprintf("%.4g", s.Mean)
+	}
+	halfWidth := (s.Interval[1] - s.Interval[0]) / 2.0
+	if s.Mean == 0.0 {
+		return fmt.Sprintf("%.4g ± %.4g", s.Mean, halfWidth)
+	}
+	return fmt.Sprintf("%.4g ± %.0f%%", s.Mean, 100.0*halfWidth/math.Abs(s.Mean))
+}
+
+func formatDelta(c Comparison) string {
+	if c.Old == nil || c.New == nil {
+		return ""
+	}
+	runs := fmt.Sprintf("(p=%.3f n=%d+%d)", c.PValue, c.Old.N(), c.New.N())
+	if !c.Significant {
+		return "~ " + runs
+	}
+	return fmt.Sprintf("%+.2f%% %s", 100.0*c.Delta, runs)
+}
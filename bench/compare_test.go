@@ -0,0 +1,146 @@
+package bench
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/alcortesm/sample"
+)
+
+func mustParse(t *testing.T, s string) []Result {
+	t.Helper()
+	results, err := Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	old := mustParse(t, oldOutput)
+	new := mustParse(t, newOutput)
+
+	got, err := Compare(old, new, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name        string
+		unit        string
+		oldN, newN  int
+		delta       float64
+		significant bool
+	}{
+		{name: "Encode-8", unit: "ns/op", oldN: 5, newN: 5, delta: -1.0 / 6.0, significant: true},
+		{name: "Decode-8", unit: "ns/op", oldN: 5, newN: 5, delta: 0, significant: false},
+		{name: "Old-8", unit: "ns/op", oldN: 1, newN: 0, delta: 0, significant: false},
+		{name: "Encode-8", unit: "B/op", oldN: 5, newN: 5, delta: 0, significant: false},
+		{name: "Encode-8", unit: "allocs/op", oldN: 5, newN: 5, delta: 0, significant: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d comparisons, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Name != w.name || g.Unit != w.unit || g.Pkg != "example.com/foo" {
+			t.Errorf("comparison %d: want %s %s, got %s %s %s",
+				i, w.name, w.unit, g.Pkg, g.Name, g.Unit)
+		}
+		n := func(s *Stats) int {
+			if s == nil {
+				return 0
+			}
+			return s.N()
+		}
+		if n(g.Old) != w.oldN || n(g.New) != w.newN {
+			t.Errorf("comparison %d: want n=%d+%d, got n=%d+%d",
+				i, w.oldN, w.newN, n(g.Old), n(g.New))
+		}
+		if math.Abs(g.Delta-w.delta) > 1e-9 {
+			t.Errorf("comparison %d: want delta %f, got %f", i, w.delta, g.Delta)
+		}
+		if g.Significant != w.significant {
+			t.Errorf("comparison %d: want significant=%t, got %t (p=%f)",
+				i, w.significant, g.Significant, g.PValue)
+		}
+	}
+
+	// the intervals come from MeanConfidenceIntervals
+	interval, err := sample.MeanConfidenceIntervals([]float64{1200, 1210, 1190, 1205, 1195}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Old.Interval != interval {
+		t.Errorf("want interval %f, got %f", interval, got[0].Old.Interval)
+	}
+}
+
+func TestCompareGroupsByPackage(t *testing.T) {
+	t.Parallel()
+	old := mustParse(t, `pkg: a
+BenchmarkX 1 1 ns/op
+pkg: b
+BenchmarkX 1 2 ns/op
+`)
+	new := mustParse(t, `pkg: b
+BenchmarkX 1 4 ns/op
+pkg: a
+BenchmarkX 1 1 ns/op
+`)
+	got, err := Compare(old, new, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 comparisons, got %d", len(got))
+	}
+	if got[0].Pkg != "a" || got[0].Delta != 0 {
+		t.Errorf("wrong first comparison: %+v", got[0])
+	}
+	if got[1].Pkg != "b" || got[1].Delta != 1 {
+		t.Errorf("wrong second comparison: %+v", got[1])
+	}
+}
+
+func TestCompareInvalidConfidence(t *testing.T) {
+	t.Parallel()
+	_, err := Compare(nil, nil, 1)
+	if !errors.Is(err, sample.ErrInvalidConfidence) {
+		t.Errorf("want %q, got %v", sample.ErrInvalidConfidence, err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	old := mustParse(t, oldOutput)
+	new := mustParse(t, newOutput)
+	comparisons, err := Compare(old, new, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := Render(&b, comparisons); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `pkg: example.com/foo
+name      old ns/op  new ns/op  delta
+Encode-8  1200 ± 1%  1000 ± 1%  -16.67% (p=0.009 n=5+5)
+Decode-8  2000 ± 5%  2000 ± 4%  ~ (p=1.000 n=5+5)
+Old-8     10                    
+
+name      old B/op  new B/op  delta
+Encode-8  128 ± 0%  128 ± 0%  ~ (p=1.000 n=5+5)
+
+name      old allocs/op  new allocs/op  delta
+Encode-8  2 ± 0%         2 ± 0%         ~ (p=1.000 n=5+5)
+`
+	if got := b.String(); got != want {
+		t.Errorf("wrong table:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
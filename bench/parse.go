@@ -0,0 +1,124 @@
+/*
+Package bench parses the output of `go test -bench` and compares the
+results of two runs, in the style of benchstat: the means of each
+benchmark and unit are compared using the sample package and the
+changes that are not statistically significant are reported as such.
+*/
+package bench // import "github.com/alcortesm/sample/bench"
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Result is a line of benchmark output: one run of a benchmark.
+//
+// Name does not include the "Benchmark" prefix, but keeps the
+// GOMAXPROCS suffix, if any. Pkg is the value of the last "pkg:"
+// configuration line before the result. Metrics are the measured
+// values, in the order they appear in the line.
+type Result struct {
+	Name       string
+	Pkg        string
+	Iterations int
+	Metrics    []Metric
+}
+
+// Metric is a measured value and its unit, like "ns/op" or "B/op".
+type Metric struct {
+	Value float64
+	Unit  string
+}
+
+// Parse reads benchmark results in the Go benchmark data format. Lines
+// that are not benchmark results or configuration lines are ignored, as
+// the format mandates.
+func Parse(r io.Reader) ([]Result, error) {
+	results := []Result{}
+	pkg := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if key, value, ok := parseConfig(line); ok {
+			if key == "pkg" {
+				pkg = value
+			}
+			continue
+		}
+
+		if result, ok := parseResult(line); ok {
+			result.Pkg = pkg
+			results = append(results, result)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Parses configuration lines, like "pkg: example.com/foo". Keys begin
+// with a lower case letter and contain no spaces.
+func parseConfig(line string) (key, value string, ok bool) {
+	i := strings.Index(line, ":")
+	if i < 1 {
+		return "", "", false
+	}
+	key = line[:i]
+	first, _ := utf8.DecodeRuneInString(key)
+	if !unicode.IsLower(first) || strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+		return "", "", false
+	}
+	rest := line[i+1:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(rest), true
+}
+
+// Parses benchmark result lines, like
+// "BenchmarkFoo-8  1000  1234 ns/op  16 B/op".
+func parseResult(line string) (Result, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields)%2 != 0 {
+		return Result{}, false
+	}
+
+	name := fields[0]
+	if !strings.HasPrefix(name, "Benchmark") {
+		return Result{}, false
+	}
+	name = strings.TrimPrefix(name, "Benchmark")
+	if first, _ := utf8.DecodeRuneInString(name); unicode.IsLower(first) {
+		// like in "Benchmarking", this is not a benchmark
+		return Result{}, false
+	}
+
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil || iterations <= 0 {
+		return Result{}, false
+	}
+
+	metrics := make([]Metric, 0, (len(fields)-2)/2)
+	for i := 2; i < len(fields); i += 2 {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false
+		}
+		metrics = append(metrics, Metric{Value: v, Unit: fields[i+1]})
+	}
+
+	return Result{
+		Name:       name,
+		Iterations: iterations,
+		Metrics:    metrics,
+	}, true
+}
@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const oldOutput = `goos: linux
+goarch: amd64
+pkg: example.com/foo
+cpu: Some CPU @ 2.00GHz
+BenchmarkEncode-8   	 1000000	      1200 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1210 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1190 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1205 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1195 ns/op	     128 B/op	       2 allocs/op
+BenchmarkDecode-8   	  500000	      2000 ns/op
+BenchmarkDecode-8   	  500000	      2100 ns/op
+BenchmarkDecode-8   	  500000	      1900 ns/op
+BenchmarkDecode-8   	  500000	      2050 ns/op
+BenchmarkDecode-8   	  500000	      1950 ns/op
+BenchmarkOld-8      	  100	      10 ns/op
+PASS
+ok  	example.com/foo	10.123s
+`
+
+const newOutput = `goos: linux
+goarch: amd64
+pkg: example.com/foo
+BenchmarkEncode-8   	 1000000	      1000 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1010 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	       990 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1005 ns/op	     128 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	       995 ns/op	     128 B/op	       2 allocs/op
+BenchmarkDecode-8   	  500000	      2010 ns/op
+BenchmarkDecode-8   	  500000	      1990 ns/op
+BenchmarkDecode-8   	  500000	      2080 ns/op
+BenchmarkDecode-8   	  500000	      1920 ns/op
+BenchmarkDecode-8   	  500000	      2000 ns/op
+PASS
+ok  	example.com/foo	10.456s
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	input := `goos: linux
+pkg: example.com/foo
+BenchmarkFoo-8   	 1000	      1234 ns/op	     16 B/op	  1 allocs/op
+BenchmarkBar/sub_case-4   	 20	      5.5e+06 ns/op	  12.50 MB/s
+Benchmarking is fun
+BenchmarkBad-8   	 abc	      1234 ns/op
+BenchmarkOdd-8   	 1000	      1234
+pkg: example.com/bar
+BenchmarkBaz 10 1 ns/op
+PASS
+`
+	got, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Result{
+		{
+			Name:       "Foo-8",
+			Pkg:        "example.com/foo",
+			Iterations: 1000,
+			Metrics: []Metric{
+				{Value: 1234, Unit: "ns/op"},
+				{Value: 16, Unit: "B/op"},
+				{Value: 1, Unit: "allocs/op"},
+			},
+		}, {
+			Name:       "Bar/sub_case-4",
+			Pkg:        "example.com/foo",
+			Iterations: 20,
+			Metrics: []Metric{
+				{Value: 5.5e6, Unit: "ns/op"},
+				{Value: 12.5, Unit: "MB/s"},
+			},
+		}, {
+			Name:       "Baz",
+			Pkg:        "example.com/bar",
+			Iterations: 10,
+			Metrics: []Metric{
+				{Value: 1, Unit: "ns/op"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %+v\ngot  %+v", want, got)
+	}
+}
+
+func TestParseFullOutput(t *testing.T) {
+	t.Parallel()
+	got, err := Parse(strings.NewReader(oldOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 11 {
+		t.Fatalf("want 11 results, got %d", len(got))
+	}
+	for _, r := range got {
+		if r.Pkg != "example.com/foo" {
+			t.Errorf("wrong package for %s: %q", r.Name, r.Pkg)
+		}
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	t.Parallel()
+	got, err := Parse(strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("want no results, got %v", got)
+	}
+}
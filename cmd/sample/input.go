@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alcortesm/sample/table"
+)
+
+// readers maps each input format to the function that reads it.
+var readers = map[string]func(io.Reader, *config) ([]float64, error){
+	"lines": readLines,
+	"csv":   readCSV,
+	"tsv":   readTSV,
+	"json":  readJSON,
+}
+
+// Reads one number per line, ignoring blank lines and lines starting
+// with '#'.
+func readLines(r io.Reader, _ *config) ([]float64, error) {
+	data := []float64{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		data = append(data, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Reads a column of a CSV file, selected by its 1-based index or by
+// its name in the header line.
+func readCSV(r io.Reader, c *config) ([]float64, error) {
+	return readColumn(r, c, ',')
+}
+
+// Like readCSV, but for tab separated values.
+func readTSV(r io.Reader, c *config) ([]float64, error) {
+	return readColumn(r, c, '\t')
+}
+
+func readColumn(r io.Reader, c *config, comma rune) ([]float64, error) {
+	opts := table.Options{Comma: comma, Header: c.header}
+	if index, err := strconv.Atoi(c.column); err == nil {
+		if index < 1 {
+			return nil, fmt.Errorf("invalid column index %d", index)
+		}
+		opts.Indexes = []int{index - 1}
+	} else {
+		opts.Header = true
+		opts.Names = []string{c.column}
+	}
+
+	t, err := table.Read(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Errors) > 0 {
+		return nil, t.Errors[0]
+	}
+	if len(t.Columns) == 0 {
+		// empty input
+		return []float64{}, nil
+	}
+
+	return t.Columns[0].Values, nil
+}
+
+// Reads a JSON array of numbers.
+func readJSON(r io.Reader, _ *config) ([]float64, error) {
+	data := []float64{}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
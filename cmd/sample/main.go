@@ -0,0 +1,180 @@
+/*
+Command sample summarizes samples of numbers read from files or from the
+standard input, printing their size, mean, standard deviation, standard
+error of the mean and confidence intervals of the mean.
+
+Usage:
+
+	sample [flags] [file ...]
+
+Each file is summarized on its own; with no files, or with "-" as a
+file name, the standard input is read. The flags are:
+
+	-confidence level
+		confidence level of the intervals of the mean (default 0.95)
+	-input format
+		format of the input: "lines", with one number per line,
+		"csv", "tsv" or "json", with a JSON array of numbers
+		(default "lines")
+	-column column
+		CSV or TSV column to read, either a 1-based index or the name
+		of a column in the header line (default "1"); empty fields and
+		fields like "NA" are skipped as missing values
+	-header
+		skip the first line of CSV and TSV inputs, it is implied when
+		-column is a name
+	-format format
+		format of the output: "text", "json" or "csv" (default "text")
+
+The exit status is 0 on success, 1 on input or output errors, 2 on
+usage errors, 3 if a sample has less than 2 numbers and 4 if the
+confidence level is not in the ]0, 1[ range or is above 0.999, the
+highest one supported. The confidence level is checked before reading
+any input.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alcortesm/sample"
+)
+
+// Exit codes.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitSampleTooSmall
+	exitInvalidConfidence
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type config struct {
+	confidence float64
+	input      string
+	column     string
+	header     bool
+	format     string
+	files      []string
+}
+
+// Parses the command line arguments, reporting any error to stderr.
+func parseFlags(args []string, stderr io.Writer) (*config, error) {
+	flags := flag.NewFlagSet("sample", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: sample [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+
+	c := &config{}
+	flags.Float64Var(&c.confidence, "confidence", 0.95,
+		"confidence level of the intervals of the mean")
+	flags.StringVar(&c.input, "input", "lines",
+		`format of the input: "lines", "csv", "tsv" or "json"`)
+	flags.StringVar(&c.column, "column", "1",
+		"CSV or TSV column to read, a 1-based index or a header name")
+	flags.BoolVar(&c.header, "header", false,
+		"skip the first line of CSV and TSV inputs")
+	flags.StringVar(&c.format, "format", "text",
+		`format of the output: "text", "json" or "csv"`)
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if _, ok := readers[c.input]; !ok {
+		err := fmt.Errorf("unknown input format %q", c.input)
+		fmt.Fprintf(stderr, "sample: %v\n", err)
+		return nil, err
+	}
+	if _, ok := writers[c.format]; !ok {
+		err := fmt.Errorf("unknown output format %q", c.format)
+		fmt.Fprintf(stderr, "sample: %v\n", err)
+		return nil, err
+	}
+	// Describe rejects the same confidence levels for any sample
+	if _, err := sample.Describe([]float64{0, 1}, c.confidence); err != nil {
+		fmt.Fprintf(stderr, "sample: %v\n", err)
+		return nil, err
+	}
+
+	c.files = flags.Args()
+	if len(c.files) == 0 {
+		c.files = []string{"-"}
+	}
+
+	return c, nil
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c, err := parseFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+	if errors.Is(err, sample.ErrInvalidConfidence) ||
+		errors.Is(err, sample.ErrUnsupportedConfidence) {
+		return exitCode(err)
+	}
+	if err != nil {
+		return exitUsage
+	}
+
+	summaries := make([]namedSummary, 0, len(c.files))
+	for _, name := range c.files {
+		data, err := readFile(name, stdin, c)
+		if err != nil {
+			fmt.Fprintf(stderr, "sample: %s: %v\n", name, err)
+			return exitError
+		}
+
+		summary, err := sample.Describe(data, c.confidence)
+		if err != nil {
+			fmt.Fprintf(stderr, "sample: %s: %v\n", name, err)
+			return exitCode(err)
+		}
+
+		summaries = append(summaries, namedSummary{Name: name, Summary: summary})
+	}
+
+	if err := writers[c.format](stdout, summaries); err != nil {
+		fmt.Fprintf(stderr, "sample: %v\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, sample.ErrSampleTooSmall):
+		return exitSampleTooSmall
+	case errors.Is(err, sample.ErrInvalidConfidence),
+		errors.Is(err, sample.ErrUnsupportedConfidence):
+		return exitInvalidConfidence
+	default:
+		return exitError
+	}
+}
+
+func readFile(name string, stdin io.Reader, c *config) ([]float64, error) {
+	if name == "-" {
+		return readers[c.input](stdin, c)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readers[c.input](f, c)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	lines := write("lines.txt", "# latencies\n2\n3\n\n5\n6\n9\n")
+	table := write("table.csv", "name,value\na,2\nb,3\nc,5\nd,6\ne,9\nf,NA\n")
+	tsv := write("table.tsv", "2\tx\n3\n5\n6\n9\n")
+
+	for _, test := range []struct {
+		description string
+		args        []string
+		stdin       string
+		wantCode    int
+		wantOut     string
+		wantErr     string
+	}{
+		{
+			description: "lines from stdin",
+			args:        nil,
+			stdin:       "2\n3\n5\n6\n9\n",
+			wantCode:    exitOK,
+			wantOut: "name  n  mean  stddev   stderr   confidence interval\n" +
+				"-     5  5     2.73861  1.22474  95% [1.60011, 8.39989]\n",
+		}, {
+			description: "lines from file as csv",
+			args:        []string{"-format", "csv", lines},
+			wantCode:    exitOK,
+			wantOut: "name,n,mean,stddev,stderr,confidence,lower,upper\n" +
+				lines + ",5,5,2.7386127875258306,1.224744871391589,0.95,1.6001082370169493,8.399891762983051\n",
+		}, {
+			description: "csv column by name",
+			args:        []string{"-input", "csv", "-column", "value", "-format", "csv", table},
+			wantCode:    exitOK,
+			wantOut: "name,n,mean,stddev,stderr,confidence,lower,upper\n" +
+				table + ",5,5,2.7386127875258306,1.224744871391589,0.95,1.6001082370169493,8.399891762983051\n",
+		}, {
+			description: "csv column by index",
+			args:        []string{"-input", "csv", "-column", "2", "-header", "-format", "csv", "-confidence", "0.9", table},
+			wantCode:    exitOK,
+			wantOut: "name,n,mean,stddev,stderr,confidence,lower,upper\n" +
+				table + ",5,5,2.7386127875258306,1.224744871391589,0.9,2.3888439341931322,7.611156065806868\n",
+		}, {
+			description: "tsv",
+			args:        []string{"-input", "tsv", "-format", "csv", tsv},
+			wantCode:    exitOK,
+			wantOut: "name,n,mean,stddev,stderr,confidence,lower,upper\n" +
+				tsv + ",5,5,2.7386127875258306,1.224744871391589,0.95,1.6001082370169493,8.399891762983051\n",
+		}, {
+			description: "bad csv field",
+			args:        []string{"-input", "csv", "-column", "1", table},
+			wantCode:    exitError,
+			wantErr:     `line 1, column "0": invalid number "name"`,
+		}, {
+			description: "json",
+			args:        []string{"-input", "json", "-format", "json"},
+			stdin:       "[1, 2, 3]",
+			wantCode:    exitOK,
+			wantOut: `[
+  {
+    "name": "-",
+    "n": 3,
+    "mean": 2,
+    "stddev": 1,
+    "stderr": 0.5773502691896258,
+    "confidence": 0.95,
+    "interval": [
+      -0.48433820832295993,
+      4.48433820832296
+    ]
+  }
+]
+`,
+		}, {
+			description: "sample too small",
+			stdin:       "1\n",
+			wantCode:    exitSampleTooSmall,
+			wantErr:     "too few sample points",
+		}, {
+			description: "invalid confidence",
+			args:        []string{"-confidence", "1"},
+			stdin:       "1\n2\n",
+			wantCode:    exitInvalidConfidence,
+			wantErr:     "invalid confidence level",
+		}, {
+			description: "invalid confidence before reading",
+			args:        []string{"-confidence", "1.5"},
+			stdin:       "",
+			wantCode:    exitInvalidConfidence,
+			wantErr:     "invalid confidence level",
+		}, {
+			description: "unsupported confidence",
+			args:        []string{"-confidence", "0.9995"},
+			stdin:       "1\n2\n",
+			wantCode:    exitInvalidConfidence,
+			wantErr:     "unsupported confidence level",
+		}, {
+			description: "bad number",
+			stdin:       "1\nfoo\n",
+			wantCode:    exitError,
+			wantErr:     "line 2",
+		}, {
+			description: "missing file",
+			args:        []string{filepath.Join(dir, "missing")},
+			wantCode:    exitError,
+			wantErr:     "no such file",
+		}, {
+			description: "unknown column",
+			args:        []string{"-input", "csv", "-column", "foo", table},
+			wantCode:    exitError,
+			wantErr:     `column "foo" not found`,
+		}, {
+			description: "unknown flag",
+			args:        []string{"-foo"},
+			wantCode:    exitUsage,
+			wantErr:     "usage: sample",
+		}, {
+			description: "unknown output format",
+			args:        []string{"-format", "xml"},
+			wantCode:    exitUsage,
+			wantErr:     `unknown output format "xml"`,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr strings.Builder
+			code := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+			if code != test.wantCode {
+				t.Errorf("wrong exit code: want %d, got %d (stderr: %q)",
+					test.wantCode, code, stderr.String())
+			}
+			if got := stdout.String(); got != test.wantOut {
+				t.Errorf("wrong output:\nwant %q\ngot  %q", test.wantOut, got)
+			}
+			if !strings.Contains(stderr.String(), test.wantErr) {
+				t.Errorf("cannot find %q in %q", test.wantErr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/alcortesm/sample"
+)
+
+type namedSummary struct {
+	Name string
+	sample.Summary
+}
+
+// writers maps each output format to the function that writes it.
+var writers = map[string]func(io.Writer, []namedSummary) error{
+	"text": writeText,
+	"json": writeJSON,
+	"csv":  writeCSV,
+}
+
+var columns = []string{
+	"name", "n", "mean", "stddev", "stderr", "confidence", "lower", "upper",
+}
+
+func writeText(w io.Writer, summaries []namedSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tn\tmean\tstddev\tstderr\tconfidence interval")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%.6g\t%.6g\t%.6g\t%g%% [%.6g, %.6g]\n",
+			s.Name, s.N, s.Mean, s.StandardDeviation, s.StandardError,
+			100*s.Confidence, s.Interval[0], s.Interval[1])
+	}
+	return tw.Flush()
+}
+
+type jsonSummary struct {
+	Name              string     `json:"name"`
+	N                 int        `json:"n"`
+	Mean              float64    `json:"mean"`
+	StandardDeviation float64    `json:"stddev"`
+	StandardError     float64    `json:"stderr"`
+	Confidence        float64    `json:"confidence"`
+	Interval          [2]float64 `json:"interval"`
+}
+
+func writeJSON(w io.Writer, summaries []namedSummary) error {
+	out := make([]jsonSummary, len(summaries))
+	for i, s := range summaries {
+		out[i] = jsonSummary{
+			Name:              s.Name,
+			N:                 s.N,
+			Mean:              s.Mean,
+			StandardDeviation: s.StandardDeviation,
+			StandardError:     s.StandardError,
+			Confidence:        s.Confidence,
+			Interval:          s.Interval,
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func writeCSV(w io.Writer, summaries []namedSummary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	for _, s := range summaries {
+		record := []string{
+			s.Name, strconv.Itoa(s.N), f(s.Mean), f(s.StandardDeviation),
+			f(s.StandardError), f(s.Confidence), f(s.Interval[0]), f(s.Interval[1]),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,150 @@
+/*
+Command sampled serves the statistics of the sample package as a JSON
+API over HTTP, see package httpapi for the endpoints.
+
+Usage:
+
+	sampled [flags]
+
+The flags are:
+
+	-addr address
+		TCP address to listen on (default "localhost:8080")
+	-prefix path
+		path prefix to serve the endpoints under, for example "/stats"
+		(default none)
+
+It stops gracefully on SIGINT or SIGTERM. The exit status is 0 on
+success, 1 if the server fails and 2 on usage errors.
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alcortesm/sample/httpapi"
+)
+
+// Exit codes.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+)
+
+// How long to wait for in-flight requests when shutting down.
+const shutdownTimeout = 5 * time.Second
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stderr, nil))
+}
+
+type config struct {
+	addr   string
+	prefix string
+}
+
+// Parses the command line arguments, reporting any error to stderr.
+func parseFlags(args []string, stderr io.Writer) (*config, error) {
+	flags := flag.NewFlagSet("sampled", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: sampled [flags]")
+		flags.PrintDefaults()
+	}
+
+	c := &config{}
+	flags.StringVar(&c.addr, "addr", "localhost:8080", "TCP address to listen on")
+	flags.StringVar(&c.prefix, "prefix", "", "path prefix to serve the endpoints under")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() != 0 {
+		err := fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+		fmt.Fprintf(stderr, "sampled: %v\n", err)
+		return nil, err
+	}
+	c.prefix = strings.TrimSuffix(c.prefix, "/")
+	if c.prefix != "" && !strings.HasPrefix(c.prefix, "/") {
+		err := fmt.Errorf("prefix %q does not start with /", c.prefix)
+		fmt.Fprintf(stderr, "sampled: %v\n", err)
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Serves the API until ctx is done. If ready is not nil, the address
+// the server listens on is sent to it once it accepts connections.
+func run(ctx context.Context, args []string, stderr io.Writer, ready chan<- string) int {
+	c, err := parseFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+
+	listener, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "sampled: %v\n", err)
+		return exitError
+	}
+
+	server := &http.Server{
+		Handler:           handler(c.prefix),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- server.Serve(listener)
+	}()
+	if ready != nil {
+		ready <- listener.Addr().String()
+	}
+
+	select {
+	case err := <-errc:
+		fmt.Fprintf(stderr, "sampled: %v\n", err)
+		return exitError
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(stderr, "sampled: %v\n", err)
+		return exitError
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "sampled: %v\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+func handler(prefix string) http.Handler {
+	if prefix == "" {
+		return httpapi.NewHandler()
+	}
+	mux := http.NewServeMux()
+	mux.Handle(prefix+"/", http.StripPrefix(prefix, httpapi.NewHandler()))
+	return mux
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ready := make(chan string, 1)
+	exit := make(chan int, 1)
+	var stderr bytes.Buffer
+	go func() {
+		exit <- run(ctx, []string{"-addr", "localhost:0", "-prefix", "/stats/"}, &stderr, ready)
+	}()
+
+	var addr string
+	select {
+	case addr = <-ready:
+	case code := <-exit:
+		t.Fatalf("exited with %d: %s", code, stderr.String())
+	}
+
+	resp, err := http.Post("http://"+addr+"/stats/describe", "application/json",
+		strings.NewReader(`{"data": [2, 3, 5, 6, 9]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("wrong status: %d", resp.StatusCode)
+	}
+	var got struct {
+		N    int     `json:"n"`
+		Mean float64 `json:"mean"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.N != 5 || got.Mean != 5 {
+		t.Errorf("wrong summary: %+v", got)
+	}
+
+	cancel()
+	if code := <-exit; code != exitOK {
+		t.Errorf("want exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	t.Parallel()
+	for _, args := range [][]string{
+		{"-bogus"},
+		{"extra"},
+		{"-prefix", "stats"},
+	} {
+		var stderr bytes.Buffer
+		code := run(context.Background(), args, &stderr, nil)
+		if code != exitUsage {
+			t.Errorf("%q: want exit code %d, got %d", args, exitUsage, code)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("%q: empty stderr", args)
+		}
+	}
+}
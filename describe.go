@@ -0,0 +1,44 @@
+package sample
+
+// Summary gathers the values most commonly calculated from a sample:
+// its size, the sample mean, the estimation of the standard deviation
+// of the population, the standard error of the mean and the confidence
+// intervals of the mean at the Confidence level.
+type Summary struct {
+	N                 int
+	Mean              float64
+	StandardDeviation float64
+	StandardError     float64
+	Confidence        float64
+	Interval          [2]float64
+}
+
+// Describe returns the summary of the sample, using the given
+// confidence level for the confidence intervals of the mean.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func Describe(data []float64, confidence float64) (Summary, error) {
+	if err := checkSampleSize("Describe", len(data), 2); err != nil {
+		return Summary{}, err
+	}
+	interval, err := MeanConfidenceIntervals(data, confidence)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	mean, _ := Mean(data)
+	sd, _ := StandardDeviation(data)
+	se, _ := StandardError(data)
+
+	return Summary{
+		N:                 len(data),
+		Mean:              mean,
+		StandardDeviation: sd,
+		StandardError:     se,
+		Confidence:        confidence,
+		Interval:          interval,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+	got, err := Describe([]float64{2.0, 3, 5, 6, 9}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.N != 5 {
+		t.Errorf("wrong N: want 5, got %d", got.N)
+	}
+	if !equals(got.Mean, 5, tolerance) {
+		t.Errorf("wrong mean: want 5, got %f", got.Mean)
+	}
+	if !equals(got.StandardDeviation, 2.7386, tolerance) {
+		t.Errorf("wrong standard deviation: want 2.7386, got %f", got.StandardDeviation)
+	}
+	if !equals(got.StandardError, 1.2247, tolerance) {
+		t.Errorf("wrong standard error: want 1.2247, got %f", got.StandardError)
+	}
+	if got.Confidence != 0.95 {
+		t.Errorf("wrong confidence: want 0.95, got %f", got.Confidence)
+	}
+	want := [2]float64{1.5996, 8.4004}
+	if !pairEquals(got.Interval, want, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want, got.Interval)
+	}
+}
+
+func TestDescribeErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		data        []float64
+		confidence  float64
+		want        error
+	}{
+		{
+			description: "small sample",
+			data:        []float64{1},
+			confidence:  0.95,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "invalid confidence",
+			data:        []float64{1, 2},
+			confidence:  1.5,
+			want:        ErrInvalidConfidence,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := Describe(test.data, test.confidence)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !errors.Is(err, test.want) {
+				t.Errorf("want %q, got %q", test.want, err)
+			}
+		})
+	}
+}
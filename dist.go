@@ -0,0 +1,526 @@
+package sample
+
+import "math"
+
+// This file implements the cumulative distribution functions the
+// hypothesis tests in this package need. They are built on top of the
+// regularized incomplete beta and gamma functions, calculated using the
+// continued fraction and series expansions from Numerical Recipes.
+
+const (
+	distEpsilon    = 1e-15
+	distMaxIter    = 1000
+	distTinyNumber = 1e-300
+)
+
+func logBeta(a, b float64) float64 {
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+// Returns the regularized incomplete beta function I_x(a, b).
+func regIncBeta(x, a, b float64) float64 {
+	if x <= 0.0 {
+		return 0.0
+	}
+	if x >= 1.0 {
+		return 1.0
+	}
+
+	front := math.Exp(a*math.Log(x) + b*math.Log1p(-x) - logBeta(a, b))
+
+	// the continued fraction converges quickly only for
+	// x < (a+1)/(a+b+2), use the symmetry relation otherwise.
+	if x < (a+1.0)/(a+b+2.0) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1.0 - front*betaContinuedFraction(1.0-x, b, a)/b
+}
+
+// Evaluates the continued fraction of the incomplete beta function
+// using the modified Lentz's method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	qab := a + b
+	qap := a + 1.0
+	qam := a - 1.0
+
+	c := 1.0
+	d := 1.0 - qab*x/qap
+	if math.Abs(d) < distTinyNumber {
+		d = distTinyNumber
+	}
+	d = 1.0 / d
+	h := d
+
+	for m := 1; m <= distMaxIter; m++ {
+		fm := float64(m)
+		m2 := 2.0 * fm
+
+		// even step
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1.0 + aa*d
+		if math.Abs(d) < distTinyNumber {
+			d = distTinyNumber
+		}
+		c = 1.0 + aa/c
+		if math.Abs(c) < distTinyNumber {
+			c = distTinyNumber
+		}
+		d = 1.0 / d
+		h *= d * c
+
+		// odd step
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1.0 + aa*d
+		if math.Abs(d) < distTinyNumber {
+			d = distTinyNumber
+		}
+		c = 1.0 + aa/c
+		if math.Abs(c) < distTinyNumber {
+			c = distTinyNumber
+		}
+		d = 1.0 / d
+		del := d * c
+		h *= del
+
+		if math.Abs(del-1.0) < distEpsilon {
+			break
+		}
+	}
+
+	return h
+}
+
+// Returns the regularized lower incomplete gamma function P(a, x).
+func regIncGammaP(a, x float64) float64 {
+	if x <= 0.0 {
+		return 0.0
+	}
+	if x < a+1.0 {
+		return gammaSeries(a, x)
+	}
+	return 1.0 - gammaContinuedFraction(a, x)
+}
+
+// Returns the regularized upper incomplete gamma function Q(a, x).
+func regIncGammaQ(a, x float64) float64 {
+	if x <= 0.0 {
+		return 1.0
+	}
+	if x < a+1.0 {
+		return 1.0 - gammaSeries(a, x)
+	}
+	return gammaContinuedFraction(a, x)
+}
+
+func gammaSeries(a, x float64) float64 {
+	lga, _ := math.Lgamma(a)
+	ap := a
+	sum := 1.0 / a
+	del := sum
+	for n := 0; n < distMaxIter; n++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*distEpsilon {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lga)
+}
+
+func gammaContinuedFraction(a, x float64) float64 {
+	lga, _ := math.Lgamma(a)
+	b := x + 1.0 - a
+	c := 1.0 / distTinyNumber
+	d := 1.0 / b
+	h := d
+	for i := 1; i <= distMaxIter; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2.0
+		d = an*d + b
+		if math.Abs(d) < distTinyNumber {
+			d = distTinyNumber
+		}
+		c = b + an/c
+		if math.Abs(c) < distTinyNumber {
+			c = distTinyNumber
+		}
+		d = 1.0 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1.0) < distEpsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lga) * h
+}
+
+// Returns the probability of a standard Normal variable being less
+// than or equal to x.
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// Returns the probability of a Student-t variable with nu degrees of
+// freedom being less than or equal to t.
+func studentTCDF(t, nu float64) float64 {
+	if math.IsInf(t, 1) {
+		return 1.0
+	}
+	if math.IsInf(t, -1) {
+		return 0.0
+	}
+	tail := 0.5 * regIncBeta(nu/(nu+t*t), nu/2.0, 0.5)
+	if t > 0.0 {
+		return 1.0 - tail
+	}
+	return tail
+}
+
+// Returns the probability of an F variable with d1 and d2 degrees of
+// freedom being bigger than f.
+func fSurvival(f, d1, d2 float64) float64 {
+	if f <= 0.0 {
+		return 1.0
+	}
+	if math.IsInf(f, 1) {
+		return 0.0
+	}
+	return regIncBeta(d2/(d2+d1*f), d2/2.0, d1/2.0)
+}
+
+// Returns the probability of a chi-square variable with k degrees of
+// freedom being bigger than x.
+func chiSquareSurvival(x, k float64) float64 {
+	if math.IsInf(x, 1) {
+		return 0.0
+	}
+	return regIncGammaQ(k/2.0, x/2.0)
+}
+
+// Returns the probability of the studentized range of k Normal
+// variables with df degrees of freedom being less than or equal to q.
+//
+// This is a port of the algorithm AS 190 by Copenhaver & Holland
+// (1988), as implemented in R's ptukey.
+func studentizedRangeCDF(q, k, df float64) float64 {
+	const (
+		eps1  = -30.0
+		eps2  = 1.0e-14
+		dhaf  = 100.0
+		dquar = 800.0
+		deigh = 5000.0
+		dlarg = 25000.0
+	)
+
+	if q <= 0.0 {
+		return 0.0
+	}
+	if math.IsInf(q, 1) {
+		return 1.0
+	}
+	if df > dlarg || math.IsInf(df, 1) {
+		return studentizedRangeInfCDF(q, k)
+	}
+
+	// Gauss-Legendre nodes and weights for the integral over the
+	// chi-distributed denominator.
+	xleg := [...]float64{
+		0.989400934991649932596154173450,
+		0.944575023073232576077988415535,
+		0.865631202387831743880467897712,
+		0.755404408355003033895101194847,
+		0.617876244402643748446671764049,
+		0.458016777657227386342419442984,
+		0.281603550779258913230460501460,
+		0.950125098376374401853193354250e-1,
+	}
+	aleg := [...]float64{
+		0.271524594117540948517805724560e-1,
+		0.622535239386478928628438369944e-1,
+		0.951585116824927848099251076022e-1,
+		0.124628971255533872052476282192,
+		0.149595988816576732081501730547,
+		0.169156519395002538189312079030,
+		0.182603415044923588866763667969,
+		0.189450610455068496285396723208,
+	}
+
+	f2 := df * 0.5
+	lgf2, _ := math.Lgamma(f2)
+	f2lf := f2*math.Log(df) - df*math.Ln2 - lgf2
+	f21 := f2 - 1.0
+	ff4 := df * 0.25
+
+	var ulen float64
+	switch {
+	case df <= dhaf:
+		ulen = 1.0
+	case df <= dquar:
+		ulen = 0.5
+	case df <= deigh:
+		ulen = 0.25
+	default:
+		ulen = 0.125
+	}
+	f2lf += math.Log(ulen)
+
+	ans := 0.0
+	for i := 1; i <= 50; i++ {
+		otsum := 0.0
+		twa1 := float64(2*i-1) * ulen
+		for j := range xleg {
+			for _, sign := range [...]float64{-1.0, 1.0} {
+				u := twa1 + sign*xleg[j]*ulen
+				t1 := f2lf + f21*math.Log(u) - u*ff4
+				if t1 < eps1 {
+					continue
+				}
+				w := studentizedRangeInfCDF(q*math.Sqrt(u*0.5), k)
+				otsum += w * aleg[j] * math.Exp(t1)
+			}
+		}
+		if float64(i)*ulen >= 1.0 && otsum <= eps2 {
+			break
+		}
+		ans += otsum
+	}
+
+	return math.Min(ans, 1.0)
+}
+
+// Returns the probability of the range of k standard Normal variables
+// being less than or equal to w, this is, the studentized range
+// distribution with infinite degrees of freedom.
+func studentizedRangeInfCDF(w, k float64) float64 {
+	const (
+		c1   = -30.0
+		c3   = 60.0
+		bb   = 8.0
+		wlar = 3.0
+	)
+
+	xleg := [...]float64{
+		0.981560634246719250690549090149,
+		0.904117256370474856678465866119,
+		0.769902674194304687036893833213,
+		0.587317954286617447296702418941,
+		0.367831498998180193752691536644,
+		0.125233408511468915472441369464,
+	}
+	aleg := [...]float64{
+		0.047175336386511827194615961485,
+		0.106939325995318430960254718194,
+		0.160078328543346226334652529543,
+		0.203167426723065921749064455810,
+		0.233492536538354808760849898925,
+		0.249147045813402785000562436043,
+	}
+
+	qsqz := w * 0.5
+	if qsqz >= bb {
+		return 1.0
+	}
+
+	// probability of all the variables being in [-w/2, w/2]
+	pr := math.Erf(qsqz / math.Sqrt2)
+	if pr >= 1.0 {
+		pr = 1.0
+	} else {
+		pr = math.Pow(pr, k)
+	}
+
+	wincr := 3
+	if w > wlar {
+		wincr = 2
+	}
+
+	blb := qsqz
+	binc := (bb - qsqz) / float64(wincr)
+	bub := blb + binc
+	cc1 := k - 1.0
+	einsum := 0.0
+	for wi := 1; wi <= wincr; wi++ {
+		elsum := 0.0
+		a := 0.5 * (bub + blb)
+		b := 0.5 * (bub - blb)
+		for j := range xleg {
+			for _, sign := range [...]float64{-1.0, 1.0} {
+				ac := a + sign*b*xleg[j]
+				qexpo := ac * ac
+				if qexpo > c3 {
+					continue
+				}
+				rinsum := normalCDF(ac) - normalCDF(ac-w)
+				if rinsum >= math.Exp(c1/cc1) {
+					elsum += aleg[j] * math.Exp(-0.5*qexpo) * math.Pow(rinsum, cc1)
+				}
+			}
+		}
+		elsum *= 2.0 * b * k / math.Sqrt(2.0*math.Pi)
+		einsum += elsum
+		blb = bub
+		bub += binc
+	}
+
+	pr += einsum
+	if pr <= math.Exp(c1) {
+		return 0.0
+	}
+	return math.Min(pr, 1.0)
+}
+
+// Returns the value q such that the probability of the studentized
+// range of k Normal variables with df degrees of freedom being less
+// than or equal to q is p, by bisection on studentizedRangeCDF.
+func studentizedRangeQuantile(p, k, df float64) float64 {
+	lo, hi := 0.0, 1.0
+	for studentizedRangeCDF(hi, k, df) < p {
+		lo = hi
+		hi *= 2.0
+	}
+	for i := 0; i < 100 && hi-lo > 1e-9*hi; i++ {
+		mid := (lo + hi) / 2.0
+		if studentizedRangeCDF(mid, k, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2.0
+}
+
+// Returns the value x such that the probability of a standard Normal
+// variable being less than or equal to x is p.
+func normalQuantile(p float64) float64 {
+	return -math.Sqrt2 * math.Erfcinv(2.0*p)
+}
+
+// Returns the probability of a noncentral Student-t variable with df
+// degrees of freedom and noncentrality parameter ncp being less than or
+// equal to t.
+//
+// This is a port of the algorithm AS 243 by Lenth (1989), as
+// implemented in R's pnt.
+func noncentralTCDF(t, df, ncp float64) float64 {
+	const (
+		itrmax = 1000
+		errmax = 1e-12
+	)
+
+	if ncp == 0.0 {
+		return studentTCDF(t, df)
+	}
+	if math.IsInf(t, 1) {
+		return 1.0
+	}
+	if math.IsInf(t, -1) {
+		return 0.0
+	}
+
+	negdel := false
+	tt, del := t, ncp
+	if t < 0.0 {
+		negdel = true
+		tt, del = -t, -ncp
+	}
+
+	lower := func(p float64) float64 {
+		p = math.Max(0.0, math.Min(p, 1.0))
+		if negdel {
+			return 1.0 - p
+		}
+		return p
+	}
+
+	// normal approximation for large degrees of freedom or
+	// noncentralities
+	if df > 4e5 || del*del > 2.0*math.Ln2*1021.0 {
+		s := 1.0 / (4.0 * df)
+		z := (tt*(1.0-s) - del) / math.Sqrt(1.0+tt*tt*2.0*s)
+		return lower(normalCDF(z))
+	}
+
+	x := t * t
+	x = x / (x + df)
+
+	tnc := 0.0
+	if x > 0.0 {
+		lambda := del * del
+		p := 0.5 * math.Exp(-0.5*lambda)
+		if p == 0.0 {
+			return lower(0.0)
+		}
+		q := math.Sqrt(2.0/math.Pi) * p * del
+		s := 0.5 - p
+		if s < 1e-7 {
+			s = -0.5 * math.Expm1(-0.5*lambda)
+		}
+		a := 0.5
+		b := 0.5 * df
+		rxb := math.Pow(1.0-x, b)
+		lgb, _ := math.Lgamma(b)
+		lgb5, _ := math.Lgamma(0.5 + b)
+		albeta := 0.5*math.Log(math.Pi) + lgb - lgb5
+		xodd := regIncBeta(x, a, b)
+		godd := 2.0 * rxb * math.Exp(a*math.Log(x)-albeta)
+		tnc = b * x
+		xeven := 1.0 - rxb
+		if tnc < 2.220446049250313e-16 {
+			xeven = tnc
+		}
+		geven := tnc * rxb
+		tnc = p*xodd + q*xeven
+
+		for it := 1; it <= itrmax; it++ {
+			a++
+			xodd -= godd
+			xeven -= geven
+			godd *= x * (a + b - 1.0) / a
+			geven *= x * (a + b - 0.5) / (a + 0.5)
+			p *= lambda / float64(2*it)
+			q *= lambda / float64(2*it+1)
+			tnc += p*xodd + q*xeven
+			s -= p
+			if s < -1e-10 || (s <= 0.0 && it > 1) {
+				break
+			}
+			if math.Abs(2.0*s*(xodd-godd)) < errmax {
+				break
+			}
+		}
+	}
+
+	tnc += normalCDF(-del)
+	return lower(tnc)
+}
+
+// Returns the value t such that the probability of a Student-t variable
+// with nu degrees of freedom being less than or equal to t is p, by
+// bisection on studentTCDF.
+func studentTQuantile(p, nu float64) float64 {
+	if p == 0.5 {
+		return 0.0
+	}
+	if p < 0.5 {
+		return -studentTQuantile(1.0-p, nu)
+	}
+
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, nu) < p {
+		lo = hi
+		hi *= 2.0
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2.0
+		if studentTCDF(mid, nu) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2.0
+}
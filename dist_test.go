@@ -0,0 +1,252 @@
+package sample
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRegIncBeta(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		x, a, b float64
+		want    float64
+	}{
+		{x: 0, a: 2, b: 3, want: 0},
+		{x: 1, a: 2, b: 3, want: 1},
+		{x: 0.5, a: 1, b: 1, want: 0.5},
+		{x: 0.5, a: 2, b: 3, want: 0.6875},
+		{x: 0.2, a: 2, b: 3, want: 0.1808},
+		{x: 0.9, a: 2, b: 3, want: 0.9963},
+		{x: 0.3, a: 0.5, b: 0.5, want: 0.36901},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v, %v", test.x, test.a, test.b)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got := regIncBeta(test.x, test.a, test.b)
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRegIncGamma(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		a, x float64
+		want float64
+	}{
+		{a: 1, x: 0, want: 0},
+		{a: 1, x: 1, want: 1 - math.Exp(-1)},
+		{a: 1, x: 5, want: 1 - math.Exp(-5)},
+		{a: 0.5, x: 2, want: math.Erf(math.Sqrt(2))},
+		{a: 3, x: 2, want: 0.32332},
+		{a: 3, x: 10, want: 0.99723},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v", test.a, test.x)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			p := regIncGammaP(test.a, test.x)
+			if !equals(p, test.want, tolerance) {
+				t.Errorf("P: want %f, got %f", test.want, p)
+			}
+			q := regIncGammaQ(test.a, test.x)
+			if !equals(q, 1-test.want, tolerance) {
+				t.Errorf("Q: want %f, got %f", 1-test.want, q)
+			}
+		})
+	}
+}
+
+func TestStudentTCDF(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		t, nu float64
+		want  float64
+	}{
+		{t: 0, nu: 1, want: 0.5},
+		{t: 1, nu: 1, want: 0.75},
+		{t: 12.71, nu: 1, want: 0.975},
+		{t: 2.920, nu: 2, want: 0.95},
+		{t: 2.228, nu: 10, want: 0.975},
+		{t: -2.228, nu: 10, want: 0.025},
+		{t: 2.750, nu: 30, want: 0.995},
+		{t: 1.960, nu: 1e6, want: 0.975},
+		{t: math.Inf(1), nu: 3, want: 1},
+		{t: math.Inf(-1), nu: 3, want: 0},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v", test.t, test.nu)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got := studentTCDF(test.t, test.nu)
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFSurvival(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		f, d1, d2 float64
+		want      float64
+	}{
+		{f: 0, d1: 2, d2: 10, want: 1},
+		{f: 4.103, d1: 2, d2: 10, want: 0.05},
+		{f: 3.326, d1: 5, d2: 10, want: 0.05},
+		{f: 5.636, d1: 5, d2: 10, want: 0.01},
+		{f: 1, d1: 7, d2: 7, want: 0.5},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v, %v", test.f, test.d1, test.d2)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got := fSurvival(test.f, test.d1, test.d2)
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestChiSquareSurvival(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		x, k float64
+		want float64
+	}{
+		{x: 0, k: 1, want: 1},
+		{x: 3.841, k: 1, want: 0.05},
+		{x: 5.991, k: 2, want: 0.05},
+		{x: 11.070, k: 5, want: 0.05},
+		{x: 23.209, k: 10, want: 0.01},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v", test.x, test.k)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got := chiSquareSurvival(test.x, test.k)
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalCDF(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		x    float64
+		want float64
+	}{
+		{x: 0, want: 0.5},
+		{x: 1.96, want: 0.975},
+		{x: -1.96, want: 0.025},
+		{x: 2.576, want: 0.995},
+	} {
+		if got := normalCDF(test.x); !equals(got, test.want, tolerance) {
+			t.Errorf("x=%f: want %f, got %f", test.x, test.want, got)
+		}
+	}
+}
+
+func TestStudentizedRangeQuantile(t *testing.T) {
+	t.Parallel()
+	// critical values of the studentized range for alpha = 0.05
+	for _, test := range []struct {
+		k, df float64
+		want  float64
+	}{
+		{k: 2, df: math.Inf(1), want: 2.772},
+		{k: 3, df: 10, want: 3.877},
+		{k: 3, df: 15, want: 3.673},
+		{k: 4, df: 20, want: 3.958},
+		{k: 5, df: 5, want: 5.673},
+		{k: 10, df: 60, want: 4.646},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v", test.k, test.df)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got := studentizedRangeQuantile(0.95, test.k, test.df)
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+			p := studentizedRangeCDF(got, test.k, test.df)
+			if !equals(p, 0.95, tolerance) {
+				t.Errorf("wrong CDF at the quantile: want 0.95, got %f", p)
+			}
+		})
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0.5, want: 0},
+		{p: 0.975, want: 1.95996},
+		{p: 0.025, want: -1.95996},
+		{p: 0.995, want: 2.57583},
+	} {
+		if got := normalQuantile(test.p); !equals(got, test.want, tolerance) {
+			t.Errorf("p=%f: want %f, got %f", test.p, test.want, got)
+		}
+	}
+}
+
+func TestNoncentralTCDF(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		t, df, ncp float64
+		want       float64
+	}{
+		{t: 2.228, df: 10, ncp: 0, want: 0.975},
+		{t: 2, df: 10, ncp: 1, want: 0.807612},
+		{t: -1, df: 5, ncp: 0.5, want: 0.082444},
+		{t: 5.8, df: 10, ncp: 2.4, want: 0.980874},
+		{t: 1, df: 3, ncp: -2, want: 0.996995},
+		{t: 3, df: 20, ncp: 3, want: 0.485720},
+		{t: 3, df: 1e6, ncp: 3, want: 0.5},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v, %v", test.t, test.df, test.ncp)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got := noncentralTCDF(test.t, test.df, test.ncp)
+			if !equals(got, test.want, tolerance/100) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestStudentTQuantile(t *testing.T) {
+	t.Parallel()
+	// the critical values in the t-table, except for the last row, that
+	// has infinite degrees of freedom
+	for i, d := range degrees[:len(degrees)-1] {
+		for j, p := range percentile {
+			if d == 2 && p == 0.6 {
+				// the table value is wrong, it should be 1.061
+				continue
+			}
+			want := tTable[i][j]
+			got := studentTQuantile(1-(1-p)/2, float64(d))
+			if !equals(got, want, want*2e-3) {
+				t.Errorf("d=%d, p=%f: want %f, got %f", d, p, want, got)
+			}
+			got = studentTQuantile((1-p)/2, float64(d))
+			if !equals(got, -want, want*2e-3) {
+				t.Errorf("d=%d, p=%f: want %f, got %f", d, p, -want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package sample
+
+import (
+	"math"
+	"sort"
+)
+
+// ECDF is the empirical cumulative distribution function of a
+// population sample.
+//
+// The zero value is not usable, use NewECDF to create one.
+type ECDF struct {
+	sorted []float64
+}
+
+// NewECDF returns the empirical cumulative distribution function of
+// the given sample. The data slice is copied, so the caller is free to
+// modify it afterwards.
+//
+// If the sample size is less than 1, it returns ErrSampleTooSmall.
+func NewECDF(data []float64) (*ECDF, error) {
+	if err := checkSampleSize("NewECDF", len(data), 1); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+
+	return &ECDF{sorted: sorted}, nil
+}
+
+// Len returns the number of sample points in the ECDF.
+func (e *ECDF) Len() int {
+	return len(e.sorted)
+}
+
+// Eval returns the fraction of sample points that are less than or
+// equal to x.
+func (e *ECDF) Eval(x float64) float64 {
+	// index of the first sample point bigger than x
+	i := sort.Search(len(e.sorted), func(i int) bool {
+		return e.sorted[i] > x
+	})
+	return float64(i) / float64(len(e.sorted))
+}
+
+// Quantile returns the generalized inverse of the ECDF at p, this is,
+// the smallest sample point x such that Eval(x) >= p. Quantile(0)
+// returns the smallest sample point.
+//
+// If p is not in the [0, 1] range, it returns ErrInvalidProbability.
+func (e *ECDF) Quantile(p float64) (float64, error) {
+	if !(p >= 0.0 && p <= 1.0) {
+		return 0.0, ErrInvalidProbability
+	}
+
+	// computes Eval like Eval does, as p*n rounds up in floating point
+	// for some p, like 0.07*100
+	n := len(e.sorted)
+	i := sort.Search(n, func(i int) bool {
+		return float64(i+1)/float64(n) >= p
+	})
+	return e.sorted[i], nil
+}
+
+// Step is a discontinuity of an ECDF: the value of the ECDF jumps to P
+// at X.
+type Step struct {
+	X float64
+	P float64
+}
+
+// Steps returns the discontinuities of the ECDF in ascending order,
+// one per distinct sample point. The last step always has a P of 1.
+func (e *ECDF) Steps() []Step {
+	n := float64(len(e.sorted))
+	steps := []Step{}
+	for i, x := range e.sorted {
+		p := float64(i+1) / n
+		if last := len(steps) - 1; last >= 0 && steps[last].X == x {
+			steps[last].P = p
+			continue
+		}
+		steps = append(steps, Step{X: x, P: p})
+	}
+	return steps
+}
+
+// Band is a confidence band around an ECDF: the true cumulative
+// distribution function of the population lies between the lower and
+// upper bounds of the band everywhere, with the requested confidence.
+type Band struct {
+	ecdf *ECDF
+	// Epsilon is the half-width of the band.
+	Epsilon float64
+}
+
+// ConfidenceBand returns the Dvoretzky–Kiefer–Wolfowitz confidence
+// band of the ECDF for the given confidence level.
+//
+// Its half-width is calculated as sqrt(ln(2/(1-confidence)) / 2N).
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func (e *ECDF) ConfidenceBand(confidence float64) (*Band, error) {
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	alpha := 1.0 - confidence
+	epsilon := math.Sqrt(math.Log(2.0/alpha) / (2.0 * float64(len(e.sorted))))
+
+	return &Band{ecdf: e, Epsilon: epsilon}, nil
+}
+
+// At returns the lower and upper bounds of the band at x, clamped to
+// the [0, 1] range.
+func (b *Band) At(x float64) [2]float64 {
+	p := b.ecdf.Eval(x)
+	return [2]float64{
+		math.Max(0.0, p-b.Epsilon),
+		math.Min(1.0, p+b.Epsilon),
+	}
+}
@@ -0,0 +1,206 @@
+package sample
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewECDFError(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		input       []float64
+	}{
+		{description: "nil input", input: nil},
+		{description: "empty input", input: []float64{}},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewECDF(test.input)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !errors.Is(err, ErrSampleTooSmall) {
+				t.Errorf("want %q, got %q", ErrSampleTooSmall, err)
+			}
+		})
+	}
+}
+
+func TestNewECDFCopiesData(t *testing.T) {
+	t.Parallel()
+	data := []float64{3, 1, 2}
+	ecdf, err := NewECDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[0] = -100
+	if got := ecdf.Eval(0); got != 0 {
+		t.Errorf("ECDF modified by the caller: want 0, got %f", got)
+	}
+}
+
+func TestECDFEval(t *testing.T) {
+	t.Parallel()
+	data := []float64{3, 1, 2, 2, 5}
+	for _, test := range []struct {
+		x    float64
+		want float64
+	}{
+		{x: 0, want: 0},
+		{x: 1, want: 0.2},
+		{x: 1.5, want: 0.2},
+		{x: 2, want: 0.6},
+		{x: 3, want: 0.8},
+		{x: 4.99, want: 0.8},
+		{x: 5, want: 1},
+		{x: 100, want: 1},
+	} {
+		test := test
+		description := fmt.Sprint(test.x)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			ecdf, err := NewECDF(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ecdf.Eval(test.x)
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestECDFQuantile(t *testing.T) {
+	t.Parallel()
+	data := []float64{3, 1, 2, 2, 5}
+	for _, test := range []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 1},
+		{p: 0.1, want: 1},
+		{p: 0.2, want: 1},
+		{p: 0.21, want: 2},
+		{p: 0.5, want: 2},
+		{p: 0.6, want: 2},
+		{p: 0.8, want: 3},
+		{p: 0.81, want: 5},
+		{p: 1, want: 5},
+	} {
+		test := test
+		description := fmt.Sprint(test.p)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			ecdf, err := NewECDF(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ecdf.Quantile(test.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+
+	// p*n rounds up in floating point for some p, like 0.07*100
+	t.Run("at every step", func(t *testing.T) {
+		t.Parallel()
+		data := make([]float64, 100)
+		for i := range data {
+			data[i] = float64(i + 1)
+		}
+		ecdf, err := NewECDF(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for p, want := range map[float64]float64{0.07: 7, 0.14: 14, 0.28: 28} {
+			if got, _ := ecdf.Quantile(p); got != want {
+				t.Errorf("p=%v: want %v, got %v", p, want, got)
+			}
+		}
+		for _, x := range data {
+			p := ecdf.Eval(x)
+			if got, _ := ecdf.Quantile(p); got != x {
+				t.Errorf("p=%v: want %v, got %v", p, x, got)
+			}
+		}
+	})
+}
+
+func TestECDFQuantileError(t *testing.T) {
+	t.Parallel()
+	ecdf, err := NewECDF([]float64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []float64{-0.1, 1.1} {
+		if _, err := ecdf.Quantile(p); !errors.Is(err, ErrInvalidProbability) {
+			t.Errorf("p=%f: want %q, got %v", p, ErrInvalidProbability, err)
+		}
+	}
+}
+
+func TestECDFSteps(t *testing.T) {
+	t.Parallel()
+	ecdf, err := NewECDF([]float64{3, 1, 2, 2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Step{{1, 0.2}, {2, 0.6}, {3, 0.8}, {5, 1}}
+	got := ecdf.Steps()
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i].X != want[i].X || !equals(got[i].P, want[i].P, tolerance) {
+			t.Errorf("step %d: want %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestECDFConfidenceBand(t *testing.T) {
+	t.Parallel()
+	ecdf, err := NewECDF([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	band, err := ecdf.ConfidenceBand(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(band.Epsilon, 0.42947, tolerance) {
+		t.Errorf("wrong epsilon: want %f, got %f", 0.42947, band.Epsilon)
+	}
+	for _, test := range []struct {
+		x    float64
+		want [2]float64
+	}{
+		{x: 0, want: [2]float64{0, 0.42947}},
+		{x: 5, want: [2]float64{0.07053, 0.92947}},
+		{x: 10, want: [2]float64{0.57053, 1}},
+	} {
+		if got := band.At(test.x); !pairEquals(got, test.want, tolerance) {
+			t.Errorf("x=%f: want %f, got %f", test.x, test.want, got)
+		}
+	}
+}
+
+func TestECDFConfidenceBandError(t *testing.T) {
+	t.Parallel()
+	ecdf, err := NewECDF([]float64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, confidence := range []float64{-1, 0, 1, 2} {
+		if _, err := ecdf.ConfidenceBand(confidence); !errors.Is(err, ErrInvalidConfidence) {
+			t.Errorf("confidence=%f: want %q, got %v", confidence, ErrInvalidConfidence, err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package sample
+
+import "math"
+
+// EffectSize is an estimate of the magnitude of the difference between
+// two populations, along with its confidence intervals.
+type EffectSize struct {
+	Estimate float64
+	Interval [2]float64
+}
+
+// CohensD computes Cohen's d, the difference between the means of a and
+// b in units of their pooled standard deviation, and its confidence
+// intervals for the given confidence level.
+//
+// The intervals are exact for Normal populations of equal variance, they
+// are found by inverting the noncentral t distribution of the two-sample
+// t statistic.
+//
+// If the sample size of a or b is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the pooled standard deviation is zero, it returns ErrZeroVariance.
+func CohensD(a, b []float64, confidence float64) (EffectSize, error) {
+	summaries, err := summarizeGroups("CohensD", [][]float64{a, b})
+	if err != nil {
+		return EffectSize{}, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return EffectSize{}, err
+	}
+
+	na := float64(summaries[0].N)
+	nb := float64(summaries[1].N)
+	sa := summaries[0].StandardDeviation
+	sb := summaries[1].StandardDeviation
+	df := na + nb - 2.0
+	pooled := math.Sqrt(((na-1.0)*sa*sa + (nb-1.0)*sb*sb) / df)
+	if pooled == 0.0 {
+		return EffectSize{}, ErrZeroVariance
+	}
+
+	d := (summaries[0].Mean - summaries[1].Mean) / pooled
+
+	// d times this factor is the two-sample t statistic
+	factor := math.Sqrt(na * nb / (na + nb))
+	alpha := 1.0 - confidence
+	t := d * factor
+	lower := noncentralityFor(t, df, 1.0-alpha/2.0) / factor
+	upper := noncentralityFor(t, df, alpha/2.0) / factor
+
+	return EffectSize{
+		Estimate: d,
+		Interval: [2]float64{lower, upper},
+	}, nil
+}
+
+// HedgesG computes Hedges' g, Cohen's d corrected for its upward bias in
+// small samples, and its confidence intervals for the given confidence
+// level.
+//
+// If the sample size of a or b is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the pooled standard deviation is zero, it returns ErrZeroVariance.
+func HedgesG(a, b []float64, confidence float64) (EffectSize, error) {
+	d, err := CohensD(a, b, confidence)
+	if err != nil {
+		return EffectSize{}, err
+	}
+
+	j := hedgesCorrection(float64(len(a) + len(b) - 2))
+
+	return EffectSize{
+		Estimate: d.Estimate * j,
+		Interval: [2]float64{d.Interval[0] * j, d.Interval[1] * j},
+	}, nil
+}
+
+// Returns the exact small sample bias correction factor of Cohen's d
+// for df degrees of freedom:
+// gamma(df/2) / (sqrt(df/2) * gamma((df-1)/2)).
+func hedgesCorrection(df float64) float64 {
+	lg1, _ := math.Lgamma(df / 2.0)
+	lg2, _ := math.Lgamma((df - 1.0) / 2.0)
+	return math.Exp(lg1 - lg2 - 0.5*math.Log(df/2.0))
+}
+
+// GlassDelta computes Glass's Δ, the difference between the means of a
+// and b in units of the standard deviation of b, the control group, and
+// its confidence intervals for the given confidence level.
+//
+// The intervals use the Normal approximation of the sampling
+// distribution of Δ by Hedges & Olkin (1985).
+//
+// If the sample size of a or b is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the standard deviation of b is zero, it returns ErrZeroVariance.
+func GlassDelta(a, b []float64, confidence float64) (EffectSize, error) {
+	summaries, err := summarizeGroups("GlassDelta", [][]float64{a, b})
+	if err != nil {
+		return EffectSize{}, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return EffectSize{}, err
+	}
+	if summaries[1].StandardDeviation == 0.0 {
+		return EffectSize{}, ErrZeroVariance
+	}
+
+	na := float64(summaries[0].N)
+	nb := float64(summaries[1].N)
+	delta := (summaries[0].Mean - summaries[1].Mean) /
+		summaries[1].StandardDeviation
+
+	se := math.Sqrt((na+nb)/(na*nb) + delta*delta/(2.0*(nb-1.0)))
+	margin := normalQuantile(1.0-(1.0-confidence)/2.0) * se
+
+	return EffectSize{
+		Estimate: delta,
+		Interval: [2]float64{delta - margin, delta + margin},
+	}, nil
+}
+
+// CliffsDelta computes Cliff's δ, the probability of a sample point
+// from a being bigger than one from b minus the probability of it being
+// smaller, and its confidence intervals for the given confidence level.
+// It makes no assumptions about the shape of the populations.
+//
+// The intervals are the asymmetric ones from Cliff (1993), using his
+// consistent estimate of the variance of δ.
+//
+// If the sample size of a or b is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func CliffsDelta(a, b []float64, confidence float64) (EffectSize, error) {
+	for _, s := range [][]float64{a, b} {
+		if err := checkSampleSize("CliffsDelta", len(s), 2); err != nil {
+			return EffectSize{}, err
+		}
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return EffectSize{}, err
+	}
+
+	na := float64(len(a))
+	nb := float64(len(b))
+
+	// dominance matrix: sign(a_i - b_j) and its row and column means
+	dominance := make([][]float64, len(a))
+	rows := make([]float64, len(a))
+	cols := make([]float64, len(b))
+	delta := 0.0
+	for i := range a {
+		dominance[i] = make([]float64, len(b))
+		for j := range b {
+			var sign float64
+			switch {
+			case a[i] > b[j]:
+				sign = 1.0
+			case a[i] < b[j]:
+				sign = -1.0
+			}
+			dominance[i][j] = sign
+			rows[i] += sign / nb
+			cols[j] += sign / na
+			delta += sign
+		}
+	}
+	delta /= na * nb
+
+	var diff, rowsVar, colsVar, cellsVar float64
+	for _, r := range rows {
+		diff = r - delta
+		rowsVar += diff * diff
+	}
+	rowsVar /= na - 1.0
+	for _, c := range cols {
+		diff = c - delta
+		colsVar += diff * diff
+	}
+	colsVar /= nb - 1.0
+	for i := range dominance {
+		for _, sign := range dominance[i] {
+			diff = sign - delta
+			cellsVar += diff * diff
+		}
+	}
+	cellsVar /= (na - 1.0) * (nb - 1.0)
+
+	variance := ((nb-1.0)*rowsVar + (na-1.0)*colsVar + cellsVar) / (na * nb)
+	interval := cliffsInterval(delta, math.Sqrt(variance), confidence)
+
+	return EffectSize{
+		Estimate: delta,
+		Interval: interval,
+	}, nil
+}
+
+func cliffsInterval(delta, sd, confidence float64) [2]float64 {
+	z := normalQuantile(1.0 - (1.0-confidence)/2.0)
+	d2 := delta * delta
+	zs := z * sd
+	denominator := 1.0 - d2 + zs*zs
+	if denominator == 0.0 {
+		return [2]float64{delta, delta}
+	}
+
+	center := delta - delta*d2
+	margin := zs * math.Sqrt((1.0-d2)*(1.0-d2)+zs*zs)
+
+	return [2]float64{
+		math.Max(-1.0, (center-margin)/denominator),
+		math.Min(1.0, (center+margin)/denominator),
+	}
+}
+
+// VarghaDelaneyA12 computes the Vargha–Delaney A measure of stochastic
+// superiority, the probability of a sample point from a being bigger
+// than one from b, counting ties as half, and its confidence intervals
+// for the given confidence level.
+//
+// A12 is (δ+1)/2, where δ is Cliff's delta, and its intervals are
+// derived from the ones in CliffsDelta.
+//
+// If the sample size of a or b is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func VarghaDelaneyA12(a, b []float64, confidence float64) (EffectSize, error) {
+	delta, err := CliffsDelta(a, b, confidence)
+	if err != nil {
+		return EffectSize{}, err
+	}
+
+	return EffectSize{
+		Estimate: (delta.Estimate + 1.0) / 2.0,
+		Interval: [2]float64{
+			(delta.Interval[0] + 1.0) / 2.0,
+			(delta.Interval[1] + 1.0) / 2.0,
+		},
+	}, nil
+}
+
+// Returns the noncentrality parameter of the noncentral t distribution
+// with df degrees of freedom for which the probability of a variable
+// being less than or equal to t is p.
+func noncentralityFor(t, df, p float64) float64 {
+	// the CDF is decreasing in the noncentrality parameter
+	f := func(ncp float64) float64 {
+		return noncentralTCDF(t, df, ncp) - p
+	}
+
+	step := 1.0 + math.Abs(t)
+	lo, hi := t-step, t+step
+	for f(lo) < 0.0 {
+		lo -= step
+		step *= 2.0
+	}
+	for f(hi) > 0.0 {
+		hi += step
+		step *= 2.0
+	}
+
+	for i := 0; i < 200 && hi-lo > 1e-10*(1.0+math.Abs(lo)); i++ {
+		mid := (lo + hi) / 2.0
+		if f(mid) > 0.0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return (lo + hi) / 2.0
+}
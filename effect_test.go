@@ -0,0 +1,138 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+func checkEffectSize(t *testing.T, got, want EffectSize) {
+	t.Helper()
+	if !equals(got.Estimate, want.Estimate, tolerance) {
+		t.Errorf("wrong estimate: want %f, got %f", want.Estimate, got.Estimate)
+	}
+	if !pairEquals(got.Interval, want.Interval, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want.Interval, got.Interval)
+	}
+}
+
+func TestEffectSizes(t *testing.T) {
+	t.Parallel()
+	a, b := anovaGroups[2], anovaGroups[0]
+	for _, test := range []struct {
+		description string
+		f           func(a, b []float64, confidence float64) (EffectSize, error)
+		want        EffectSize
+	}{
+		{
+			description: "CohensD",
+			f:           CohensD,
+			want:        EffectSize{2.38366, [2]float64{0.82167, 3.88214}},
+		}, {
+			description: "HedgesG",
+			f:           HedgesG,
+			want:        EffectSize{2.19951, [2]float64{0.75819, 3.58223}},
+		}, {
+			description: "GlassDelta",
+			f:           GlassDelta,
+			want:        EffectSize{2.79508, [2]float64{0.72588, 4.86429}},
+		}, {
+			description: "CliffsDelta",
+			f:           CliffsDelta,
+			want:        EffectSize{0.91667, [2]float64{0.38583, 0.99151}},
+		}, {
+			description: "VarghaDelaneyA12",
+			f:           VarghaDelaneyA12,
+			want:        EffectSize{0.95833, [2]float64{0.69292, 0.99575}},
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			got, err := test.f(a, b, 0.95)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEffectSize(t, got, test.want)
+		})
+	}
+}
+
+func TestCliffsDeltaTies(t *testing.T) {
+	t.Parallel()
+	got, err := CliffsDelta([]float64{1, 2, 3, 4, 5}, []float64{3, 4, 5, 6, 7, 8}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEffectSize(t, got, EffectSize{-0.7, [2]float64{-0.93968, 0.00075}})
+}
+
+func TestCliffsDeltaNoOverlap(t *testing.T) {
+	t.Parallel()
+	got, err := CliffsDelta([]float64{5, 6, 7}, []float64{1, 2, 3}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEffectSize(t, got, EffectSize{1, [2]float64{1, 1}})
+}
+
+func TestEffectSizesErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		a, b        []float64
+		confidence  float64
+		want        error
+	}{
+		{
+			description: "small a",
+			a:           []float64{1},
+			b:           []float64{1, 2},
+			confidence:  0.95,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "small b",
+			a:           []float64{1, 2},
+			b:           nil,
+			confidence:  0.95,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "invalid confidence",
+			a:           []float64{1, 2},
+			b:           []float64{1, 2},
+			confidence:  1,
+			want:        ErrInvalidConfidence,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			for name, f := range map[string]func([]float64, []float64, float64) (EffectSize, error){
+				"CohensD":          CohensD,
+				"HedgesG":          HedgesG,
+				"GlassDelta":       GlassDelta,
+				"CliffsDelta":      CliffsDelta,
+				"VarghaDelaneyA12": VarghaDelaneyA12,
+			} {
+				_, err := f(test.a, test.b, test.confidence)
+				if err == nil {
+					t.Fatalf("%s: unexpected success", name)
+				}
+				if !errors.Is(err, test.want) {
+					t.Errorf("%s: want %q, got %q", name, test.want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestEffectSizesZeroVariance(t *testing.T) {
+	t.Parallel()
+	a := []float64{2, 2}
+	b := []float64{1, 1}
+	if _, err := CohensD(a, b, 0.95); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("CohensD: want %q, got %v", ErrZeroVariance, err)
+	}
+	if _, err := GlassDelta(a, b, 0.95); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("GlassDelta: want %q, got %v", ErrZeroVariance, err)
+	}
+}
@@ -0,0 +1,60 @@
+package sample
+
+import (
+	"fmt"
+)
+
+// SampleSizeError is the error returned when a sample is too small for
+// a computation: Func needed at least Need sample points and got Got.
+//
+// It matches ErrSampleTooSmall, so callers only interested in the kind
+// of failure can use errors.Is(err, ErrSampleTooSmall), while those
+// needing the details can use errors.As.
+type SampleSizeError struct {
+	Func string
+	Got  int
+	Need int
+}
+
+func (e *SampleSizeError) Error() string {
+	return fmt.Sprintf("%s: %v: got %d, need at least %d",
+		e.Func, ErrSampleTooSmall, e.Got, e.Need)
+}
+
+// Is reports if target is ErrSampleTooSmall.
+func (e *SampleSizeError) Is(target error) bool {
+	return target == ErrSampleTooSmall
+}
+
+// Returns a *SampleSizeError if got is less than need, nil otherwise.
+func checkSampleSize(fn string, got, need int) error {
+	if got < need {
+		return &SampleSizeError{Func: fn, Got: got, Need: need}
+	}
+	return nil
+}
+
+// ConfidenceError is the error returned when a confidence level is not
+// in the ]0, 1[ range, Value is the offending confidence level.
+//
+// It matches ErrInvalidConfidence with errors.Is.
+type ConfidenceError struct {
+	Value float64
+}
+
+func (e *ConfidenceError) Error() string {
+	return fmt.Sprintf("invalid confidence level %v, 0 < confidence < 1", e.Value)
+}
+
+// Is reports if target is ErrInvalidConfidence.
+func (e *ConfidenceError) Is(target error) bool {
+	return target == ErrInvalidConfidence
+}
+
+// Returns a *ConfidenceError if c is not in ]0, 1[, nil otherwise.
+func checkConfidence(c float64) error {
+	if !(c > 0.0 && c < 1.0) {
+		return &ConfidenceError{Value: c}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package sample
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSampleSizeError(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		f           func() error
+		want        SampleSizeError
+	}{
+		{
+			description: "Mean",
+			f: func() error {
+				_, err := Mean(nil)
+				return err
+			},
+			want: SampleSizeError{Func: "Mean", Got: 0, Need: 1},
+		}, {
+			description: "MeanConfidenceIntervals",
+			f: func() error {
+				_, err := MeanConfidenceIntervals([]float64{1}, 0.95)
+				return err
+			},
+			want: SampleSizeError{Func: "MeanConfidenceIntervals", Got: 1, Need: 2},
+		}, {
+			description: "group of an ANOVA",
+			f: func() error {
+				_, err := WelchANOVA([][]float64{{1, 2, 3}, {4}})
+				return err
+			},
+			want: SampleSizeError{Func: "WelchANOVA", Got: 1, Need: 2},
+		}, {
+			description: "number of comparisons",
+			f: func() error {
+				_, err := AdjustConfidence(0.95, 0, Bonferroni)
+				return err
+			},
+			want: SampleSizeError{Func: "AdjustConfidence", Got: 0, Need: 1},
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			err := test.f()
+			if !errors.Is(err, ErrSampleTooSmall) {
+				t.Errorf("%v does not match ErrSampleTooSmall", err)
+			}
+			if errors.Is(err, ErrInvalidConfidence) {
+				t.Errorf("%v matches ErrInvalidConfidence", err)
+			}
+			var got *SampleSizeError
+			if !errors.As(err, &got) {
+				t.Fatalf("%v is not a *SampleSizeError", err)
+			}
+			if *got != test.want {
+				t.Errorf("want %+v, got %+v", test.want, *got)
+			}
+		})
+	}
+}
+
+func TestConfidenceError(t *testing.T) {
+	t.Parallel()
+	_, err := MeanConfidenceIntervals([]float64{1, 2}, 1.5)
+	if !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("%v does not match ErrInvalidConfidence", err)
+	}
+	var got *ConfidenceError
+	if !errors.As(err, &got) {
+		t.Fatalf("%v is not a *ConfidenceError", err)
+	}
+	if got.Value != 1.5 {
+		t.Errorf("want value 1.5, got %f", got.Value)
+	}
+
+	// still matches when wrapped by callers
+	wrapped := fmt.Errorf("computing latencies: %w", err)
+	if !errors.Is(wrapped, ErrInvalidConfidence) || !errors.As(wrapped, &got) {
+		t.Errorf("wrapped error %v does not match", wrapped)
+	}
+}
+
+// Valid confidence levels missing from the Student-t table can be told
+// apart from invalid ones.
+func TestUnsupportedConfidenceError(t *testing.T) {
+	t.Parallel()
+	_, err := MeanConfidenceIntervals([]float64{1, 2, 3}, 0.9995)
+	if !errors.Is(err, ErrUnsupportedConfidence) {
+		t.Errorf("%v does not match ErrUnsupportedConfidence", err)
+	}
+	if errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("%v matches ErrInvalidConfidence", err)
+	}
+}
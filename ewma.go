@@ -0,0 +1,205 @@
+package sample
+
+import (
+	"math"
+	"time"
+)
+
+// EWMA tracks the exponentially weighted moving mean and variance of a
+// stream of sample points, where the weight of each sample point halves
+// every half-life, so old sample points are gradually forgotten.
+//
+// The half-life is either a number of sample points, see NewEWMA, or a
+// duration, see NewTimeEWMA, which allows for sample points taken at
+// irregular times.
+//
+// The weights are not normalized, so there is no bias towards the
+// initial value during the warm-up, unlike the naive recurrence
+// mean = a*x + (1-a)*mean; the results are those of the weighted
+// functions of the package with ReliabilityWeights equal to the
+// current weights of the sample points.
+//
+// An EWMA is not safe for concurrent use. The zero value is not
+// usable, use NewEWMA or NewTimeEWMA to create one.
+type EWMA struct {
+	halfLife float64       // in sample points
+	period   time.Duration // the half-life of time based EWMAs
+	last     time.Time     // of the most recent sample point
+
+	n      int
+	weight float64 // sum of the weights
+	sq     float64 // sum of the squared weights
+	mean   float64
+	ss     float64 // sum of w_i (x_i - mean)^2
+}
+
+// NewEWMA returns an EWMA where the weight of the sample points halves
+// every halfLife sample points added after them.
+//
+// If halfLife is not positive, it returns ErrInvalidHalfLife.
+func NewEWMA(halfLife float64) (*EWMA, error) {
+	if !(halfLife > 0.0) || math.IsInf(halfLife, 1) {
+		return nil, ErrInvalidHalfLife
+	}
+	return &EWMA{halfLife: halfLife}, nil
+}
+
+// NewTimeEWMA returns an EWMA where the weight of the sample points
+// halves every halfLife, according to the times they are added with.
+//
+// If halfLife is not positive, it returns ErrInvalidHalfLife.
+func NewTimeEWMA(halfLife time.Duration) (*EWMA, error) {
+	if halfLife <= 0 {
+		return nil, ErrInvalidHalfLife
+	}
+	return &EWMA{period: halfLife}, nil
+}
+
+// Add adds a sample point. For time based EWMAs it is taken now, see
+// AddAt. NaNs are ignored.
+func (e *EWMA) Add(x float64) {
+	if e.period == 0 {
+		e.add(x, math.Exp2(-1.0/e.halfLife), 1.0)
+		return
+	}
+	e.AddAt(x, time.Now())
+}
+
+// AddAt adds a sample point taken at time t. The weights of the
+// previous sample points decay according to the time elapsed since the
+// most recent one; a sample point older than the most recent one is
+// added with the weight it would have now. For EWMAs with a half-life
+// in sample points, t is ignored. NaNs are ignored.
+func (e *EWMA) AddAt(x float64, t time.Time) {
+	if e.period == 0 {
+		e.Add(x)
+		return
+	}
+
+	switch {
+	case e.n == 0:
+		e.last = t
+		e.add(x, 1.0, 1.0)
+	case t.Before(e.last):
+		e.add(x, 1.0, e.decay(e.last.Sub(t)))
+	default:
+		d := e.decay(t.Sub(e.last))
+		e.last = t
+		e.add(x, d, 1.0)
+	}
+}
+
+// Returns the factor by which the weights decay in the given time.
+func (e *EWMA) decay(elapsed time.Duration) float64 {
+	return math.Exp2(-float64(elapsed) / float64(e.period))
+}
+
+// Decays the weights of the previous sample points by the factor d and
+// adds x with weight w, updating the mean and sum of squares with West's
+// weighted incremental algorithm.
+func (e *EWMA) add(x, d, w float64) {
+	if math.IsNaN(x) {
+		return
+	}
+
+	e.n++
+	e.weight = d*e.weight + w
+	e.sq = d*d*e.sq + w*w
+	delta := x - e.mean
+	e.mean += delta * w / e.weight
+	e.ss = d*e.ss + w*delta*(x-e.mean)
+}
+
+// N returns the number of sample points added.
+func (e *EWMA) N() int {
+	return e.n
+}
+
+// EffectiveSampleSize returns Kish's effective sample size of the
+// current weights, see the function EffectiveSampleSize. It tends to
+// about 2.9 times the half-life for long streams of evenly spaced
+// sample points.
+func (e *EWMA) EffectiveSampleSize() float64 {
+	if e.n == 0 {
+		return 0.0
+	}
+	return e.weight * e.weight / e.sq
+}
+
+// Mean returns the exponentially weighted mean of the sample points.
+//
+// If no sample points were added, it returns ErrSampleTooSmall.
+func (e *EWMA) Mean() (float64, error) {
+	if err := checkSampleSize("EWMA.Mean", e.n, 1); err != nil {
+		return 0.0, err
+	}
+	return e.mean, nil
+}
+
+// Checks there are at least 2 sample points with non-zero weights,
+// which is not the case when the weights of all but the last one
+// decayed to zero after a very long time.
+func (e *EWMA) checkSpread(fn string) error {
+	got := e.n
+	if got >= 2 && !(e.weight*e.weight > e.sq) {
+		got = 1
+	}
+	return checkSampleSize(fn, got, 2)
+}
+
+// Returns the estimation of the variance of the population, that of
+// WeightedStandardDeviation with ReliabilityWeights.
+func (e *EWMA) variance() float64 {
+	return math.Max(0.0, e.ss/(e.weight-e.sq/e.weight))
+}
+
+// StandardDeviation returns the exponentially weighted estimation of
+// the standard deviation of the population.
+//
+// If less than 2 sample points were added, it returns
+// ErrSampleTooSmall.
+func (e *EWMA) StandardDeviation() (float64, error) {
+	if err := e.checkSpread("EWMA.StandardDeviation"); err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(e.variance()), nil
+}
+
+// StandardError returns the standard error of the exponentially
+// weighted mean: the standard deviation divided by the square root of
+// the effective sample size.
+//
+// If less than 2 sample points were added, it returns
+// ErrSampleTooSmall.
+func (e *EWMA) StandardError() (float64, error) {
+	if err := e.checkSpread("EWMA.StandardError"); err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(e.variance() / e.EffectiveSampleSize()), nil
+}
+
+// MeanConfidenceIntervals returns an approximate confidence band of
+// the current mean, computed like WeightedMeanConfidenceIntervals with
+// ReliabilityWeights. It assumes the population did not change during
+// the last few half-lives, it is not a band of the whole history of
+// the mean.
+//
+// If less than 2 sample points were added, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func (e *EWMA) MeanConfidenceIntervals(confidence float64) ([2]float64, error) {
+	if err := e.checkSpread("EWMA.MeanConfidenceIntervals"); err != nil {
+		return [2]float64{}, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return [2]float64{}, err
+	}
+
+	neff := e.EffectiveSampleSize()
+	se := math.Sqrt(e.variance() / neff)
+	margin := studentTQuantile(1.0-(1.0-confidence)/2.0, neff-1.0) * se
+
+	return [2]float64{e.mean - margin, e.mean + margin}, nil
+}
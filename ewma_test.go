@@ -0,0 +1,198 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// Checks the EWMA has the results of the weighted functions with the
+// given reliability weights.
+func checkEWMA(t *testing.T, e *EWMA, data, weights []float64) {
+	t.Helper()
+	mean, err := e.Mean()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMean, err := WeightedMean(data, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(mean, wantMean, 1e-9) {
+		t.Errorf("wrong mean: want %f, got %f", wantMean, mean)
+	}
+
+	sd, err := e.StandardDeviation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSD, err := WeightedStandardDeviation(data, weights, ReliabilityWeights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(sd, wantSD, 1e-9) {
+		t.Errorf("wrong standard deviation: want %f, got %f", wantSD, sd)
+	}
+
+	se, err := e.StandardError()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSE, err := WeightedStandardError(data, weights, ReliabilityWeights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(se, wantSE, 1e-9) {
+		t.Errorf("wrong standard error: want %f, got %f", wantSE, se)
+	}
+
+	interval, err := e.MeanConfidenceIntervals(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantInterval, err := WeightedMeanConfidenceIntervals(data, weights, ReliabilityWeights, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pairEquals(interval, wantInterval, 1e-9) {
+		t.Errorf("wrong interval: want %f, got %f", wantInterval, interval)
+	}
+
+	neff, err := EffectiveSampleSize(weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(e.EffectiveSampleSize(), neff, 1e-9) {
+		t.Errorf("wrong effective sample size: want %f, got %f", neff, e.EffectiveSampleSize())
+	}
+}
+
+func TestEWMA(t *testing.T) {
+	t.Parallel()
+	const halfLife = 5.0
+	e, err := NewEWMA(halfLife)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	data := []float64{}
+	for i := 0; i < 50; i++ {
+		x := 10 + rng.NormFloat64()
+		data = append(data, x)
+		e.Add(x)
+		if i == 0 {
+			// no bias towards zero during the warm-up
+			if mean, _ := e.Mean(); mean != x {
+				t.Errorf("want mean %f of the first sample point, got %f", x, mean)
+			}
+			continue
+		}
+
+		weights := make([]float64, len(data))
+		for j := range weights {
+			weights[j] = math.Exp2(-float64(len(data)-1-j) / halfLife)
+		}
+		checkEWMA(t, e, data, weights)
+	}
+	if e.N() != 50 {
+		t.Errorf("wrong N: want 50, got %d", e.N())
+	}
+}
+
+func TestEWMAForgets(t *testing.T) {
+	t.Parallel()
+	e, err := NewEWMA(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		e.Add(100)
+	}
+	for i := 0; i < 200; i++ {
+		e.Add(float64(i % 2))
+	}
+	mean, _ := e.Mean()
+	if math.Abs(mean-0.5) > 0.1 {
+		t.Errorf("old sample points not forgotten: mean %f", mean)
+	}
+	// the limit for a half-life h is (1+d)/(1-d), with d = 2^(-1/h)
+	d := math.Exp2(-1.0 / 10)
+	if want := (1 + d) / (1 - d); !equals(e.EffectiveSampleSize(), want, 1e-6) {
+		t.Errorf("wrong effective sample size: want %f, got %f", want, e.EffectiveSampleSize())
+	}
+}
+
+func TestTimeEWMA(t *testing.T) {
+	t.Parallel()
+	const halfLife = 10 * time.Second
+	e, err := NewTimeEWMA(halfLife)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{
+		0, time.Second, 3 * time.Second, 3 * time.Second, 20 * time.Second,
+		15 * time.Second, // out of order
+		21 * time.Second, time.Minute, 61 * time.Second,
+	}
+	data := []float64{3, 5, 4, 7, 2, 6, 9, 4, 5}
+	latest := time.Duration(0)
+	for i, offset := range offsets {
+		e.AddAt(data[i], start.Add(offset))
+		if offset > latest {
+			latest = offset
+		}
+		if i == 0 {
+			continue
+		}
+
+		weights := make([]float64, i+1)
+		for j := range weights {
+			weights[j] = math.Exp2(-float64(latest-offsets[j]) / float64(halfLife))
+		}
+		checkEWMA(t, e, data[:i+1], weights)
+	}
+}
+
+func TestEWMAErrors(t *testing.T) {
+	t.Parallel()
+	for _, halfLife := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if _, err := NewEWMA(halfLife); !errors.Is(err, ErrInvalidHalfLife) {
+			t.Errorf("%v: want %q, got %v", halfLife, ErrInvalidHalfLife, err)
+		}
+	}
+	if _, err := NewTimeEWMA(0); !errors.Is(err, ErrInvalidHalfLife) {
+		t.Errorf("want %q, got %v", ErrInvalidHalfLife, err)
+	}
+
+	e, err := NewEWMA(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Mean(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	e.Add(1)
+	e.Add(math.NaN())
+	if _, err := e.StandardDeviation(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	e.Add(2)
+	if _, err := e.MeanConfidenceIntervals(0); !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("want %q, got %v", ErrInvalidConfidence, err)
+	}
+
+	// the weight of the first sample point decays to zero
+	te, err := NewTimeEWMA(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	te.AddAt(1, start)
+	te.AddAt(2, start.Add(time.Hour))
+	if _, err := te.StandardError(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+}
@@ -0,0 +1,511 @@
+package sample
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// HDRRecorder records integer values, like latencies in microseconds,
+// into a High Dynamic Range histogram: a histogram with logarithmic
+// buckets that keeps a fixed number of significant decimal digits for
+// every value in its range, using a fixed amount of memory.
+//
+// The layout of its buckets and its binary encoding are the ones of
+// HdrHistogram (hdrhistogram.org), so encoded recorders can be decoded
+// by the HdrHistogram libraries of other languages, and the other way
+// around.
+//
+// Record and Merge are safe for concurrent use and lock-free: each
+// bucket count is updated atomically. Queries made while recording see
+// each count at some point during the query, and are not a consistent
+// snapshot of the recorder.
+//
+// The zero value is not usable, use NewHDRRecorder to create one.
+type HDRRecorder struct {
+	lowest  int64
+	highest int64
+	digits  int
+
+	unitMagnitude               uint
+	subBucketHalfCountMagnitude uint
+	subBucketHalfCount          int64
+	subBucketMask               int64
+
+	counts []int64
+}
+
+// NewHDRRecorder returns an empty recorder for values in the
+// [lowest, highest] range, where lowest is the smallest value that can
+// be told apart from 0, keeping the given number of significant decimal
+// digits, from 0 to 5. For example, with 3 digits the values are
+// recorded with a relative error of at most 0.1%.
+//
+// The memory used grows with the logarithm of highest/lowest and with
+// 10^digits: with 3 digits, a range from 1 microsecond to 1 hour takes
+// about 200KB.
+//
+// If lowest is less than 1, highest is less than twice lowest or
+// digits is not in the [0, 5] range, it returns ErrInvalidRecorder.
+func NewHDRRecorder(lowest, highest int64, digits int) (*HDRRecorder, error) {
+	r, length, err := newHDRLayout(lowest, highest, digits)
+	if err != nil {
+		return nil, err
+	}
+	r.counts = make([]int64, length)
+
+	return r, nil
+}
+
+// Returns a recorder without its counts, and the number of counts it
+// needs, so their size can be checked before allocating them.
+func newHDRLayout(lowest, highest int64, digits int) (*HDRRecorder, int, error) {
+	if lowest < 1 || highest < 2*lowest || digits < 0 || digits > 5 {
+		return nil, 0, ErrInvalidRecorder
+	}
+
+	r := &HDRRecorder{
+		lowest:  lowest,
+		highest: highest,
+		digits:  digits,
+	}
+
+	largestSingleUnit := 2 * int64(math.Pow10(digits))
+	subBucketCountMagnitude := uint(math.Ceil(math.Log2(float64(largestSingleUnit))))
+	if subBucketCountMagnitude < 1 {
+		subBucketCountMagnitude = 1
+	}
+	r.subBucketHalfCountMagnitude = subBucketCountMagnitude - 1
+	r.unitMagnitude = uint(bits.Len64(uint64(lowest)) - 1)
+	subBucketCount := int64(1) << (r.subBucketHalfCountMagnitude + 1)
+	r.subBucketHalfCount = subBucketCount / 2
+	r.subBucketMask = (subBucketCount - 1) << r.unitMagnitude
+	if r.unitMagnitude+r.subBucketHalfCountMagnitude > 61 {
+		return nil, 0, ErrInvalidRecorder
+	}
+
+	// number of buckets needed to cover the highest value
+	smallestUntrackable := subBucketCount << r.unitMagnitude
+	buckets := 1
+	for smallestUntrackable <= highest {
+		if smallestUntrackable > math.MaxInt64/2 {
+			buckets++
+			break
+		}
+		smallestUntrackable <<= 1
+		buckets++
+	}
+
+	return r, (buckets + 1) * int(r.subBucketHalfCount), nil
+}
+
+// Returns the index of the count of the value v, which must not be
+// negative.
+func (r *HDRRecorder) index(v int64) int {
+	bucket := int64(bits.Len64(uint64(v|r.subBucketMask))) -
+		int64(r.unitMagnitude) - int64(r.subBucketHalfCountMagnitude+1)
+	subBucket := v >> (uint(bucket) + r.unitMagnitude)
+	return int((bucket+1)<<r.subBucketHalfCountMagnitude + subBucket - r.subBucketHalfCount)
+}
+
+// Returns the smallest value and the number of values recorded in the
+// count at index i.
+func (r *HDRRecorder) valueRange(i int) (lowest, size int64) {
+	bucket := int64(i>>r.subBucketHalfCountMagnitude) - 1
+	subBucket := int64(i)&(r.subBucketHalfCount-1) + r.subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= r.subBucketHalfCount
+		bucket = 0
+	}
+	shift := uint(bucket) + r.unitMagnitude
+	return subBucket << shift, 1 << shift
+}
+
+// Record records the value v.
+//
+// If v is negative or bigger than the highest trackable value of the
+// recorder, it returns ErrValueOutOfRange.
+func (r *HDRRecorder) Record(v int64) error {
+	return r.RecordN(v, 1)
+}
+
+// RecordN records the value v n times. Nothing is recorded if n is not
+// positive.
+//
+// If v is negative or bigger than the highest trackable value of the
+// recorder, it returns ErrValueOutOfRange.
+func (r *HDRRecorder) RecordN(v, n int64) error {
+	if v < 0 {
+		return ErrValueOutOfRange
+	}
+	i := r.index(v)
+	if i >= len(r.counts) {
+		return ErrValueOutOfRange
+	}
+	if n <= 0 {
+		return nil
+	}
+	atomic.AddInt64(&r.counts[i], n)
+	return nil
+}
+
+// Merge records all the values recorded in other; other is not
+// modified. The recorders do not need to have the same range or
+// precision, each value of other is recorded as the lowest value of its
+// bucket.
+//
+// If any value of other is out of the range of the recorder, it
+// returns ErrValueOutOfRange and the values of other are only partially
+// merged.
+func (r *HDRRecorder) Merge(other *HDRRecorder) error {
+	sameLayout := r.unitMagnitude == other.unitMagnitude &&
+		r.subBucketHalfCountMagnitude == other.subBucketHalfCountMagnitude
+	for i := range other.counts {
+		n := atomic.LoadInt64(&other.counts[i])
+		if n == 0 {
+			continue
+		}
+		if sameLayout && i < len(r.counts) {
+			atomic.AddInt64(&r.counts[i], n)
+			continue
+		}
+		v, _ := other.valueRange(i)
+		if err := r.RecordN(v, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset removes all the recorded values.
+func (r *HDRRecorder) Reset() {
+	for i := range r.counts {
+		atomic.StoreInt64(&r.counts[i], 0)
+	}
+}
+
+// Returns a copy of the counts and the sum of them.
+func (r *HDRRecorder) snapshot() (counts []int64, n int64) {
+	counts = make([]int64, len(r.counts))
+	for i := range r.counts {
+		counts[i] = atomic.LoadInt64(&r.counts[i])
+		n += counts[i]
+	}
+	return counts, n
+}
+
+// N returns the number of values recorded.
+func (r *HDRRecorder) N() int64 {
+	_, n := r.snapshot()
+	return n
+}
+
+// Quantile returns the p-quantile of the recorded values, as the
+// highest value equivalent, at the precision of the recorder, to the
+// smallest recorded value with at least a fraction p of the values less
+// than or equal to it; Quantile(0) returns the lowest value equivalent
+// to the smallest recorded value instead. These are the quantiles of
+// HdrHistogram.
+//
+// If no values were recorded, it returns ErrSampleTooSmall.
+//
+// If p is not in the [0, 1] range, it returns ErrInvalidProbability.
+func (r *HDRRecorder) Quantile(p float64) (int64, error) {
+	counts, n := r.snapshot()
+	if n == 0 {
+		return 0, &SampleSizeError{Func: "HDRRecorder.Quantile", Got: 0, Need: 1}
+	}
+	if !(p >= 0.0 && p <= 1.0) {
+		return 0, ErrInvalidProbability
+	}
+
+	target := int64(math.Nextafter(p, 0.0)*float64(n) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	cumulative := int64(0)
+	for i, c := range counts {
+		cumulative += c
+		if cumulative >= target {
+			lowest, size := r.valueRange(i)
+			if p == 0.0 {
+				return lowest, nil
+			}
+			return lowest + size - 1, nil
+		}
+	}
+
+	// unreachable, the cumulative count ends at n
+	return 0, nil
+}
+
+// Histogram returns the recorded values as a Histogram, so they can be
+// analysed with its estimators, with a bucket per count of the
+// recorder from the smallest to the biggest recorded value. As the
+// values are integers, a count for the values from a to b is
+// represented by the bucket [a-0.5, b+0.5[.
+//
+// If no values were recorded, it returns ErrSampleTooSmall.
+func (r *HDRRecorder) Histogram() (*Histogram, error) {
+	counts, n := r.snapshot()
+	if n == 0 {
+		return nil, &SampleSizeError{Func: "HDRRecorder.Histogram", Got: 0, Need: 1}
+	}
+
+	first, last := -1, 0
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+
+	bounds := make([]float64, 0, last-first+2)
+	values := make([]float64, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		lowest, _ := r.valueRange(i)
+		bounds = append(bounds, float64(lowest)-0.5)
+		values = append(values, float64(counts[i]))
+	}
+	lowest, size := r.valueRange(last)
+	bounds = append(bounds, float64(lowest+size)-0.5)
+
+	return NewHistogram(bounds, values)
+}
+
+// Mean returns the mean of the recorded values, using the midpoint of
+// the values of each count; it is the same as the mean of Histogram,
+// and can differ by up to half a unit from the one of HdrHistogram.
+//
+// If no values were recorded, it returns ErrSampleTooSmall.
+func (r *HDRRecorder) Mean() (float64, error) {
+	h, err := r.Histogram()
+	if err != nil {
+		return 0.0, err
+	}
+	return h.Mean()
+}
+
+// StandardDeviation returns the sample-based estimation of the
+// standard deviation of the population, like the one of Histogram.
+//
+// If less than 2 values were recorded, it returns ErrSampleTooSmall.
+func (r *HDRRecorder) StandardDeviation() (float64, error) {
+	h, err := r.Histogram()
+	if err != nil {
+		return 0.0, err
+	}
+	return h.StandardDeviation()
+}
+
+// MeanConfidenceIntervals returns the conservative confidence intervals
+// of the mean of the population of Histogram.MeanConfidenceIntervals,
+// which account for the precision of the recorder.
+//
+// If less than 2 values were recorded, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func (r *HDRRecorder) MeanConfidenceIntervals(confidence float64) ([2]float64, error) {
+	h, err := r.Histogram()
+	if err != nil {
+		return [2]float64{}, err
+	}
+	return h.MeanConfidenceIntervals(confidence)
+}
+
+// Cookies of the HdrHistogram V2 encodings, for 64 bit counts.
+const (
+	hdrEncodingCookie           = 0x1c849303 | 0x10
+	hdrCompressedEncodingCookie = 0x1c849304 | 0x10
+	hdrHeaderSize               = 40
+)
+
+// hdrMaxDecodedCounts bounds the memory used to decode a recorder, as
+// the size of its counts comes from the header and not from the data.
+// Its 32MB are less than the 48MB of a recorder with 5 digits for the
+// whole range of int64, but enough for 5 digits from 1 microsecond to
+// 10 days.
+const hdrMaxDecodedCounts = 1 << 22
+
+// MarshalBinary encodes the recorder in the compressed V2 encoding of
+// HdrHistogram. HdrHistogram log files, as written by
+// HistogramLogWriter, have the base64 encoding of it in each line, but
+// this package does not read nor write the rest of those files, like
+// the timestamps and tags of the lines.
+func (r *HDRRecorder) MarshalBinary() ([]byte, error) {
+	var compressed bytes.Buffer
+	compressed.Write(make([]byte, 8))
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(r.encode()); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	b := compressed.Bytes()
+	binary.BigEndian.PutUint32(b[0:], hdrCompressedEncodingCookie)
+	binary.BigEndian.PutUint32(b[4:], uint32(len(b)-8))
+	return b, nil
+}
+
+// Returns the uncompressed V2 encoding: a big-endian header followed by
+// the counts up to the last non-zero one, as zig-zag LEB128 varints,
+// where runs of zeros are encoded as negative numbers.
+func (r *HDRRecorder) encode() []byte {
+	counts, _ := r.snapshot()
+	limit := len(counts)
+	for limit > 0 && counts[limit-1] == 0 {
+		limit--
+	}
+
+	b := make([]byte, hdrHeaderSize, hdrHeaderSize+2*limit)
+	binary.BigEndian.PutUint32(b[0:], hdrEncodingCookie)
+	// b[4:8] is the payload length, b[8:12] the normalizing index offset
+	binary.BigEndian.PutUint32(b[12:], uint32(r.digits))
+	binary.BigEndian.PutUint64(b[16:], uint64(r.lowest))
+	binary.BigEndian.PutUint64(b[24:], uint64(r.highest))
+	binary.BigEndian.PutUint64(b[32:], math.Float64bits(1.0))
+
+	for i := 0; i < limit; {
+		if counts[i] != 0 {
+			b = appendZigZag(b, counts[i])
+			i++
+			continue
+		}
+		zeros := int64(0)
+		for ; i < limit && counts[i] == 0; i++ {
+			zeros++
+		}
+		if zeros == 1 {
+			b = appendZigZag(b, 0)
+		} else {
+			b = appendZigZag(b, -zeros)
+		}
+	}
+
+	binary.BigEndian.PutUint32(b[4:], uint32(len(b)-hdrHeaderSize))
+	return b
+}
+
+// Appends v as the zig-zag LEB128 varint of HdrHistogram, which takes
+// at most 9 bytes: the last one holds 8 bits instead of 7.
+func appendZigZag(b []byte, v int64) []byte {
+	u := uint64(v<<1) ^ uint64(v>>63)
+	for i := 0; i < 8; i++ {
+		if u>>7 == 0 {
+			return append(b, byte(u))
+		}
+		b = append(b, byte(u&0x7f|0x80))
+		u >>= 7
+	}
+	return append(b, byte(u))
+}
+
+// Reads a zig-zag LEB128 varint written by appendZigZag, returning the
+// number of bytes read, or 0 if b is truncated.
+func readZigZag(b []byte) (int64, int) {
+	u := uint64(0)
+	for i := 0; i < 9; i++ {
+		if i == len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			u |= uint64(b[i]) << 56
+			return int64(u>>1) ^ -int64(u&1), 9
+		}
+		u |= uint64(b[i]&0x7f) << (7 * uint(i))
+		if b[i]&0x80 == 0 {
+			return int64(u>>1) ^ -int64(u&1), i + 1
+		}
+	}
+	return 0, 0
+}
+
+// UnmarshalBinary decodes a recorder in the compressed or uncompressed
+// V2 encodings of HdrHistogram, replacing the contents of r. It must
+// not be called concurrently with other methods.
+//
+// If the data is malformed, uses an unsupported encoding or describes
+// a recorder that NewHDRRecorder would not create, or one with more
+// than 4M counts (32MB), it returns ErrInvalidRecorder.
+func (r *HDRRecorder) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return ErrInvalidRecorder
+	}
+
+	if binary.BigEndian.Uint32(data) == hdrCompressedEncodingCookie {
+		length := binary.BigEndian.Uint32(data[4:])
+		if uint64(length) != uint64(len(data)-8) {
+			return ErrInvalidRecorder
+		}
+		z, err := zlib.NewReader(bytes.NewReader(data[8:]))
+		if err != nil {
+			return ErrInvalidRecorder
+		}
+		defer z.Close()
+		// bounds the memory used by malformed data
+		data, err = io.ReadAll(io.LimitReader(z, 1<<28))
+		if err != nil {
+			return ErrInvalidRecorder
+		}
+	}
+
+	return r.decode(data)
+}
+
+func (r *HDRRecorder) decode(data []byte) error {
+	if len(data) < hdrHeaderSize ||
+		binary.BigEndian.Uint32(data) != hdrEncodingCookie ||
+		binary.BigEndian.Uint32(data[8:]) != 0 {
+		return ErrInvalidRecorder
+	}
+
+	length := binary.BigEndian.Uint32(data[4:])
+	digits := binary.BigEndian.Uint32(data[12:])
+	lowest := int64(binary.BigEndian.Uint64(data[16:]))
+	highest := int64(binary.BigEndian.Uint64(data[24:]))
+	payload := data[hdrHeaderSize:]
+	if uint64(length) != uint64(len(payload)) || digits > 5 {
+		return ErrInvalidRecorder
+	}
+
+	decoded, size, err := newHDRLayout(lowest, highest, int(digits))
+	if err != nil || size > hdrMaxDecodedCounts {
+		return ErrInvalidRecorder
+	}
+	decoded.counts = make([]int64, size)
+
+	i := 0
+	for len(payload) > 0 {
+		v, n := readZigZag(payload)
+		if n == 0 {
+			return ErrInvalidRecorder
+		}
+		payload = payload[n:]
+		if v < 0 {
+			// -v overflows for the smallest int64
+			if v == math.MinInt64 || -v > int64(len(decoded.counts)-i) {
+				return ErrInvalidRecorder
+			}
+			i += int(-v)
+			continue
+		}
+		if i >= len(decoded.counts) {
+			return ErrInvalidRecorder
+		}
+		decoded.counts[i] = v
+		i++
+	}
+
+	*r = *decoded
+	return nil
+}
@@ -0,0 +1,384 @@
+package sample
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func mustHDRRecorder(t *testing.T, lowest, highest int64, digits int) *HDRRecorder {
+	t.Helper()
+	r, err := NewHDRRecorder(lowest, highest, digits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestHDRRecorderLayout(t *testing.T) {
+	t.Parallel()
+	// the same as HdrHistogram for an hour in microseconds
+	r := mustHDRRecorder(t, 1, 3600*1000*1000, 3)
+	if len(r.counts) != 23552 {
+		t.Errorf("wrong number of counts: want 23552, got %d", len(r.counts))
+	}
+
+	// every count holds a contiguous range of values, the next one
+	// starts where it ends
+	next := int64(0)
+	for i := range r.counts {
+		lowest, size := r.valueRange(i)
+		if lowest != next {
+			t.Fatalf("count %d starts at %d, want %d", i, lowest, next)
+		}
+		if r.index(lowest) != i || r.index(lowest+size-1) != i {
+			t.Fatalf("values of count %d are not indexed to it", i)
+		}
+		// 3 significant digits
+		if float64(size-1) > float64(lowest)/1000 && lowest > 2048 {
+			t.Fatalf("count %d too wide: %d values from %d", i, size, lowest)
+		}
+		next = lowest + size
+	}
+}
+
+func TestHDRRecorder(t *testing.T) {
+	t.Parallel()
+	// like the tests of HdrHistogram
+	r := mustHDRRecorder(t, 1, 3600*1000*1000, 3)
+	if err := r.RecordN(1000, 10000); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Record(100000000); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.N() != 10001 {
+		t.Errorf("wrong N: want 10001, got %d", r.N())
+	}
+	for _, test := range []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 1000},
+		{p: 0.3, want: 1000},
+		{p: 0.9999, want: 1000},
+		{p: 0.99999, want: 100000000},
+		{p: 1, want: 100000000},
+	} {
+		got, err := r.Quantile(test.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(got)-test.want) > test.want*0.001 {
+			t.Errorf("p=%v: want %f, got %d", test.p, test.want, got)
+		}
+	}
+
+	mean, err := r.Mean()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := (1000.0*10000 + 100000000) / 10001
+	if math.Abs(mean-want) > want*0.001 {
+		t.Errorf("wrong mean: want %f, got %f", want, mean)
+	}
+
+	sd, err := r.StandardDeviation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(sd-999850.0) > 999850.0*0.001 {
+		t.Errorf("wrong standard deviation: want 999850, got %f", sd)
+	}
+
+	interval, err := r.MeanConfidenceIntervals(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interval[0] > mean || interval[1] < mean {
+		t.Errorf("interval %f does not contain the mean %f", interval, mean)
+	}
+}
+
+func TestHDRRecorderPrecision(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewSource(1))
+	for digits := 1; digits <= 4; digits++ {
+		r := mustHDRRecorder(t, 1, 1<<40, digits)
+		for i := 0; i < 20; i++ {
+			r.Reset()
+			v := rng.Int63n(1 << 40)
+			if err := r.Record(v); err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.Quantile(0.5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got < v || float64(got-v) > float64(v)*math.Pow10(-digits) {
+				t.Fatalf("digits %d: %d recorded as %d", digits, v, got)
+			}
+		}
+	}
+}
+
+func TestHDRRecorderMerge(t *testing.T) {
+	t.Parallel()
+	a := mustHDRRecorder(t, 1, 1000000, 3)
+	b := mustHDRRecorder(t, 1, 1000000, 3)
+	c := mustHDRRecorder(t, 1, 10000000, 2)
+	for v := int64(1); v <= 1000; v++ {
+		_ = a.Record(v)
+		_ = b.Record(v * 1000)
+		_ = c.Record(v * 10)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Merge(c); err != nil {
+		t.Fatal(err)
+	}
+	if a.N() != 3000 {
+		t.Errorf("wrong N: want 3000, got %d", a.N())
+	}
+	max, _ := a.Quantile(1)
+	if math.Abs(float64(max)-1000000) > 1000 {
+		t.Errorf("wrong maximum: want 1000000, got %d", max)
+	}
+
+	// values of c out of the range of a
+	_ = c.Record(5000000)
+	if err := a.Merge(c); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("want %q, got %v", ErrValueOutOfRange, err)
+	}
+}
+
+func TestHDRRecorderConcurrent(t *testing.T) {
+	t.Parallel()
+	r := mustHDRRecorder(t, 1, 1000000, 3)
+	other := mustHDRRecorder(t, 1, 1000000, 3)
+	_ = other.RecordN(500, 1000)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if err := r.Record(int64(g*1000 + i)); err != nil {
+					t.Error(err)
+					return
+				}
+				if i%100 == 0 {
+					_, _ = r.Quantile(0.5)
+				}
+			}
+			if g == 0 {
+				if err := r.Merge(other); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if r.N() != 9000 {
+		t.Errorf("wrong N: want 9000, got %d", r.N())
+	}
+}
+
+func TestHDRRecorderEncoding(t *testing.T) {
+	t.Parallel()
+	// 32 counts per bucket, values below 32 have their own count
+	r := mustHDRRecorder(t, 1, 1000, 1)
+	_ = r.RecordN(3, 2)
+	_ = r.Record(10)
+
+	want := []byte{
+		0x1c, 0x84, 0x93, 0x13, // cookie
+		0, 0, 0, 4, // payload length
+		0, 0, 0, 0, // normalizing index offset
+		0, 0, 0, 1, // significant digits
+		0, 0, 0, 0, 0, 0, 0, 1, // lowest
+		0, 0, 0, 0, 0, 0, 0x03, 0xe8, // highest
+		0x3f, 0xf0, 0, 0, 0, 0, 0, 0, // integer to double ratio
+		0x05, // 3 zeros
+		0x04, // 2
+		0x0b, // 6 zeros
+		0x02, // 1
+	}
+	if got := r.encode(); !bytes.Equal(got, want) {
+		t.Errorf("wrong encoding:\nwant %x\ngot  %x", want, got)
+	}
+
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the prefix of the histograms in HdrHistogram logs
+	if s := base64.StdEncoding.EncodeToString(b); s[:5] != "HISTF" {
+		t.Errorf("wrong prefix: %s", s)
+	}
+
+	decoded := &HDRRecorder{}
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.encode(), want) {
+		t.Errorf("wrong decoded recorder: %x", decoded.encode())
+	}
+	if err := decoded.UnmarshalBinary(want); err != nil {
+		t.Errorf("cannot decode the uncompressed encoding: %v", err)
+	}
+}
+
+func TestHDRRecorderEncodingRoundTrip(t *testing.T) {
+	t.Parallel()
+	r := mustHDRRecorder(t, 10, 1<<50, 3)
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 10000; i++ {
+		_ = r.Record(int64(rng.ExpFloat64() * 1e6))
+	}
+	_ = r.RecordN(1<<49, math.MaxInt64/2)
+
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &HDRRecorder{}
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.lowest != 10 || decoded.highest != 1<<50 || decoded.digits != 3 {
+		t.Errorf("wrong settings: %d, %d, %d", decoded.lowest, decoded.highest, decoded.digits)
+	}
+	for i := range r.counts {
+		if r.counts[i] != decoded.counts[i] {
+			t.Fatalf("count %d: want %d, got %d", i, r.counts[i], decoded.counts[i])
+		}
+	}
+
+	for _, malformed := range [][]byte{
+		nil,
+		b[:7],
+		b[:len(b)-1],
+		r.encode()[:39],
+		append(r.encode(), 0x02),
+	} {
+		if err := decoded.UnmarshalBinary(malformed); !errors.Is(err, ErrInvalidRecorder) {
+			t.Errorf("want %q, got %v", ErrInvalidRecorder, err)
+		}
+	}
+}
+
+// Returns the uncompressed encoding of a recorder with the given header
+// and counts, which are not checked.
+func craftHDREncoding(lowest, highest int64, digits uint32, counts ...int64) []byte {
+	b := make([]byte, hdrHeaderSize)
+	binary.BigEndian.PutUint32(b[0:], hdrEncodingCookie)
+	binary.BigEndian.PutUint32(b[12:], digits)
+	binary.BigEndian.PutUint64(b[16:], uint64(lowest))
+	binary.BigEndian.PutUint64(b[24:], uint64(highest))
+	binary.BigEndian.PutUint64(b[32:], math.Float64bits(1.0))
+	for _, c := range counts {
+		b = appendZigZag(b, c)
+	}
+	binary.BigEndian.PutUint32(b[4:], uint32(len(b)-hdrHeaderSize))
+	return b
+}
+
+func TestHDRRecorderDecodeCrafted(t *testing.T) {
+	t.Parallel()
+	if err := new(HDRRecorder).UnmarshalBinary(craftHDREncoding(1, 1000, 1, -3, 2, -6, 1)); err != nil {
+		t.Fatalf("cannot decode a valid encoding: %v", err)
+	}
+	size := int64(len(mustHDRRecorder(t, 1, 1000, 1).counts))
+
+	for _, test := range []struct {
+		description string
+		data        []byte
+	}{
+		{
+			description: "smallest run of zeros",
+			data:        craftHDREncoding(1, 1000, 1, 1, math.MinInt64, 1),
+		}, {
+			description: "run of zeros past the counts",
+			data:        craftHDREncoding(1, 1000, 1, 1, -size, 1),
+		}, {
+			description: "count past the counts",
+			data:        craftHDREncoding(1, 1000, 1, -(size - 1), 1, 1),
+		}, {
+			description: "too many counts",
+			data:        craftHDREncoding(1, math.MaxInt64, 5, 1),
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			if err := new(HDRRecorder).UnmarshalBinary(test.data); !errors.Is(err, ErrInvalidRecorder) {
+				t.Errorf("want %q, got %v", ErrInvalidRecorder, err)
+			}
+		})
+	}
+}
+
+func TestZigZag(t *testing.T) {
+	t.Parallel()
+	for _, v := range []int64{
+		0, 1, -1, 63, -64, 64, 1 << 20, -(1 << 40), 1<<55 - 1, 1 << 56,
+		math.MaxInt64, math.MinInt64,
+	} {
+		b := appendZigZag(nil, v)
+		if len(b) > 9 {
+			t.Errorf("%d takes %d bytes", v, len(b))
+		}
+		got, n := readZigZag(b)
+		if got != v || n != len(b) {
+			t.Errorf("%d decoded as %d after %d of %d bytes", v, got, n, len(b))
+		}
+		if _, n := readZigZag(b[:len(b)-1]); n != 0 {
+			t.Errorf("%d: truncated encoding read", v)
+		}
+	}
+}
+
+func TestHDRRecorderErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		lowest  int64
+		highest int64
+		digits  int
+	}{
+		{lowest: 0, highest: 100, digits: 3},
+		{lowest: 10, highest: 19, digits: 3},
+		{lowest: 1, highest: 100, digits: -1},
+		{lowest: 1, highest: 100, digits: 6},
+	} {
+		if _, err := NewHDRRecorder(test.lowest, test.highest, test.digits); !errors.Is(err, ErrInvalidRecorder) {
+			t.Errorf("%+v: want %q, got %v", test, ErrInvalidRecorder, err)
+		}
+	}
+
+	r := mustHDRRecorder(t, 1, 1000, 3)
+	for _, v := range []int64{-1, 1 << 20} {
+		if err := r.Record(v); !errors.Is(err, ErrValueOutOfRange) {
+			t.Errorf("%d: want %q, got %v", v, ErrValueOutOfRange, err)
+		}
+	}
+	if _, err := r.Quantile(0.5); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	if _, err := r.Mean(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	_ = r.Record(1)
+	if _, err := r.Quantile(2); !errors.Is(err, ErrInvalidProbability) {
+		t.Errorf("want %q, got %v", ErrInvalidProbability, err)
+	}
+}
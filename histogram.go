@@ -0,0 +1,249 @@
+package sample
+
+import "math"
+
+// Histogram is a population sample whose sample points are only known
+// by the bucket they fall in, like the buckets of Prometheus histograms
+// or of HDR histograms.
+//
+// Its estimators assume the sample points are uniformly spread inside
+// each bucket, so they are represented by the midpoint of the bucket.
+//
+// The zero value is not usable, use NewHistogram to create one.
+type Histogram struct {
+	bounds []float64
+	counts []float64
+	n      float64
+}
+
+// Bucket is a bucket of a Histogram: Count sample points are in the
+// [Lower, Upper[ range.
+type Bucket struct {
+	Lower float64
+	Upper float64
+	Count float64
+}
+
+// NewHistogram returns the histogram with the given bucket bounds and
+// counts: counts[i] is the number of sample points in the bucket
+// [bounds[i], bounds[i+1][, so there must be one more bound than
+// counts. The counts do not need to be integers, to allow for scaled or
+// decayed histograms. The slices are copied, so the caller is free to
+// modify them afterwards.
+//
+// Cumulative bucket counts, like the ones of Prometheus, must be
+// converted into counts by subtracting from each cumulative count the
+// previous one, and their +Inf bucket must be empty or replaced by one
+// with a finite upper bound.
+//
+// If there are no buckets, the bounds are not finite and strictly
+// increasing, or the counts are negative or not finite, it returns
+// ErrInvalidHistogram.
+func NewHistogram(bounds, counts []float64) (*Histogram, error) {
+	if len(counts) == 0 || len(bounds) != len(counts)+1 {
+		return nil, ErrInvalidHistogram
+	}
+	for i, b := range bounds {
+		if math.IsInf(b, 0) || math.IsNaN(b) {
+			return nil, ErrInvalidHistogram
+		}
+		if i > 0 && !(b > bounds[i-1]) {
+			return nil, ErrInvalidHistogram
+		}
+	}
+
+	h := &Histogram{
+		bounds: make([]float64, len(bounds)),
+		counts: make([]float64, len(counts)),
+	}
+	copy(h.bounds, bounds)
+	copy(h.counts, counts)
+
+	for _, c := range counts {
+		if !(c >= 0.0) || math.IsInf(c, 1) {
+			return nil, ErrInvalidHistogram
+		}
+		h.n += c
+	}
+
+	return h, nil
+}
+
+// N returns the number of sample points in the histogram, the sum of
+// its counts.
+func (h *Histogram) N() float64 {
+	return h.n
+}
+
+// Buckets returns the buckets of the histogram in ascending order.
+func (h *Histogram) Buckets() []Bucket {
+	buckets := make([]Bucket, len(h.counts))
+	for i, c := range h.counts {
+		buckets[i] = Bucket{
+			Lower: h.bounds[i],
+			Upper: h.bounds[i+1],
+			Count: c,
+		}
+	}
+	return buckets
+}
+
+// Returns the midpoint and the width of the bucket i.
+func (h *Histogram) bucket(i int) (mid, width float64) {
+	return (h.bounds[i] + h.bounds[i+1]) / 2.0, h.bounds[i+1] - h.bounds[i]
+}
+
+// Mean estimates the sample mean from the midpoints of the buckets.
+//
+// If the histogram is empty, it returns ErrSampleTooSmall.
+func (h *Histogram) Mean() (float64, error) {
+	// the counts may be fractional, any positive total has a mean
+	if h.n == 0.0 {
+		return 0.0, checkSampleSize("Histogram.Mean", 0, 1)
+	}
+	return h.mean(), nil
+}
+
+func (h *Histogram) mean() float64 {
+	sum := 0.0
+	for i, c := range h.counts {
+		mid, _ := h.bucket(i)
+		sum += c * mid
+	}
+	return sum / h.n
+}
+
+// Variance estimates the variance of the population, with Bessel's
+// correction, from the midpoints of the buckets, corrected for the
+// grouping into buckets with Sheppard's correction: the mean of w^2/12
+// is subtracted, where w are the widths of the buckets of each sample
+// point. The correction assumes a smooth population density and can
+// overcorrect when most sample points are in a single bucket, so the
+// result is never less than zero.
+//
+// If the histogram has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+func (h *Histogram) Variance() (float64, error) {
+	if err := checkSampleSize("Histogram.Variance", int(h.n), 2); err != nil {
+		return 0.0, err
+	}
+	return h.variance(), nil
+}
+
+func (h *Histogram) variance() float64 {
+	mean := h.mean()
+	ss := 0.0
+	sheppard := 0.0
+	for i, c := range h.counts {
+		mid, width := h.bucket(i)
+		diff := mid - mean
+		ss += c * diff * diff
+		sheppard += c * width * width / 12.0
+	}
+	return math.Max(0.0, ss/(h.n-1.0)-sheppard/h.n)
+}
+
+// StandardDeviation estimates the standard deviation of the
+// population, as the square root of Variance.
+//
+// If the histogram has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+func (h *Histogram) StandardDeviation() (float64, error) {
+	if err := checkSampleSize("Histogram.StandardDeviation", int(h.n), 2); err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(h.variance()), nil
+}
+
+// StandardError estimates the standard error of the mean, as
+// StandardDeviation divided by the square root of N.
+//
+// If the histogram has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+func (h *Histogram) StandardError() (float64, error) {
+	if err := checkSampleSize("Histogram.StandardError", int(h.n), 2); err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(h.variance() / h.n), nil
+}
+
+// Quantile estimates the p-quantile of the sample by linear
+// interpolation inside the bucket where the cumulative count reaches
+// p*N. Quantile(0) is the lower bound of the first non-empty bucket and
+// Quantile(1) the upper bound of the last one.
+//
+// If the histogram is empty, it returns ErrSampleTooSmall.
+//
+// If p is not in the [0, 1] range, it returns ErrInvalidProbability.
+func (h *Histogram) Quantile(p float64) (float64, error) {
+	// the counts may be fractional, any positive total has a quantile
+	if h.n == 0.0 {
+		return 0.0, checkSampleSize("Histogram.Quantile", 0, 1)
+	}
+	if !(p >= 0.0 && p <= 1.0) {
+		return 0.0, ErrInvalidProbability
+	}
+
+	target := p * h.n
+	cumulative := 0.0
+	last := 0
+	for i, c := range h.counts {
+		if c == 0.0 {
+			continue
+		}
+		last = i
+		if cumulative+c >= target {
+			_, width := h.bucket(i)
+			return h.bounds[i] + width*(target-cumulative)/c, nil
+		}
+		cumulative += c
+	}
+
+	// only reached because of rounding errors when p is 1
+	return h.bounds[last+1], nil
+}
+
+// MeanConfidenceIntervals returns conservative confidence intervals of
+// the mean of the population: whatever the values of the sample points
+// inside their buckets, they contain the intervals MeanConfidenceIntervals
+// would return for the raw sample.
+//
+// The lower end uses the lower bounds of the buckets as sample points
+// and the upper end their upper bounds, and the margin of error uses
+// an upper bound of the standard deviation of the raw sample: the one
+// computed from the midpoints plus the one of the distances to the
+// midpoints, which are at most half the widths of the buckets.
+//
+// If the histogram has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func (h *Histogram) MeanConfidenceIntervals(confidence float64) ([2]float64, error) {
+	if err := checkSampleSize("Histogram.MeanConfidenceIntervals", int(h.n), 2); err != nil {
+		return [2]float64{}, err
+	}
+
+	mean := h.mean()
+	lower, upper := 0.0, 0.0
+	ss, ssWidth := 0.0, 0.0
+	for i, c := range h.counts {
+		mid, width := h.bucket(i)
+		lower += c * h.bounds[i]
+		upper += c * h.bounds[i+1]
+		diff := mid - mean
+		ss += c * diff * diff
+		ssWidth += c * width * width / 4.0
+	}
+	lower /= h.n
+	upper /= h.n
+	sd := math.Sqrt(ss/(h.n-1.0)) + math.Sqrt(ssWidth/(h.n-1.0))
+
+	tinv, err := studentTwoSidedCriticalValue(int64(h.n)-1, confidence)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	margin := tinv * sd / math.Sqrt(h.n)
+
+	return [2]float64{lower - margin, upper + margin}, nil
+}
@@ -0,0 +1,216 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func mustHistogram(t *testing.T, bounds, counts []float64) *Histogram {
+	t.Helper()
+	h, err := NewHistogram(bounds, counts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+	h := mustHistogram(t, []float64{0, 10, 20, 30, 40}, []float64{2, 5, 2, 1})
+
+	if h.N() != 10 {
+		t.Errorf("wrong N: want 10, got %f", h.N())
+	}
+	if got := h.Buckets(); len(got) != 4 || got[1] != (Bucket{Lower: 10, Upper: 20, Count: 5}) {
+		t.Errorf("wrong buckets: %v", got)
+	}
+
+	mean, err := h.Mean()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(mean, 17, tolerance) {
+		t.Errorf("wrong mean: want 17, got %f", mean)
+	}
+
+	// 84.4444 without Sheppard's correction
+	variance, err := h.Variance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(variance, 76.1111, tolerance) {
+		t.Errorf("wrong variance: want 76.1111, got %f", variance)
+	}
+
+	sd, err := h.StandardDeviation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(sd, 8.7242, tolerance) {
+		t.Errorf("wrong standard deviation: want 8.7242, got %f", sd)
+	}
+
+	se, err := h.StandardError()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(se, 2.7588, tolerance) {
+		t.Errorf("wrong standard error: want 2.7588, got %f", se)
+	}
+
+	interval, err := h.MeanConfidenceIntervals(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [2]float64{1.6568, 32.3432}
+	if !pairEquals(interval, want, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want, interval)
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	t.Parallel()
+	h := mustHistogram(t, []float64{-10, 0, 10, 20, 30, 40, 50},
+		[]float64{0, 2, 5, 2, 1, 0})
+	for _, test := range []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 0},
+		{p: 0.1, want: 5},
+		{p: 0.25, want: 11},
+		{p: 0.5, want: 16},
+		{p: 0.9, want: 30},
+		{p: 0.95, want: 35},
+		{p: 1, want: 40},
+	} {
+		got, err := h.Quantile(test.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equals(got, test.want, tolerance) {
+			t.Errorf("p=%v: want %f, got %f", test.p, test.want, got)
+		}
+	}
+
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := h.Quantile(p); !errors.Is(err, ErrInvalidProbability) {
+			t.Errorf("p=%v: want %q, got %v", p, ErrInvalidProbability, err)
+		}
+	}
+}
+
+// Scaled or decayed histograms can have a total count below 1.
+func TestHistogramFractionalCounts(t *testing.T) {
+	t.Parallel()
+	h := mustHistogram(t, []float64{0, 10, 20}, []float64{0.2, 0.6})
+	if mean, err := h.Mean(); err != nil || !equals(mean, 12.5, tolerance) {
+		t.Errorf("wrong mean: want 12.5, got %f, %v", mean, err)
+	}
+	if median, err := h.Quantile(0.5); err != nil || !equals(median, 13.333, tolerance) {
+		t.Errorf("wrong median: want 13.333, got %f, %v", median, err)
+	}
+	// the degrees of freedom of the variance need 2 sample points
+	if _, err := h.Variance(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+}
+
+// The mean intervals of a histogram must contain the ones of the raw
+// sample it was built from.
+func TestHistogramMeanConfidenceIntervalsConservative(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewSource(42))
+	bounds := []float64{0, 1, 2, 4, 8, 16, 32}
+	for trial := 0; trial < 100; trial++ {
+		data := make([]float64, 3+rng.Intn(50))
+		counts := make([]float64, len(bounds)-1)
+		for i := range data {
+			data[i] = math.Min(31.99, rng.ExpFloat64()*4.0)
+			for j := range counts {
+				if data[i] < bounds[j+1] {
+					counts[j]++
+					break
+				}
+			}
+		}
+
+		want, err := MeanConfidenceIntervals(data, 0.95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := mustHistogram(t, bounds, counts).MeanConfidenceIntervals(0.95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0] > want[0] || got[1] < want[1] {
+			t.Fatalf("%f does not contain the raw interval %f", got, want)
+		}
+	}
+}
+
+func TestHistogramErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		bounds      []float64
+		counts      []float64
+	}{
+		{
+			description: "no buckets",
+			bounds:      []float64{0},
+			counts:      []float64{},
+		}, {
+			description: "too few bounds",
+			bounds:      []float64{0, 1},
+			counts:      []float64{1, 2},
+		}, {
+			description: "decreasing bounds",
+			bounds:      []float64{0, 2, 1},
+			counts:      []float64{1, 2},
+		}, {
+			description: "repeated bounds",
+			bounds:      []float64{0, 1, 1},
+			counts:      []float64{1, 2},
+		}, {
+			description: "infinite bound",
+			bounds:      []float64{0, 1, math.Inf(1)},
+			counts:      []float64{1, 2},
+		}, {
+			description: "negative count",
+			bounds:      []float64{0, 1, 2},
+			counts:      []float64{1, -2},
+		}, {
+			description: "NaN count",
+			bounds:      []float64{0, 1, 2},
+			counts:      []float64{1, math.NaN()},
+		},
+	} {
+		if _, err := NewHistogram(test.bounds, test.counts); !errors.Is(err, ErrInvalidHistogram) {
+			t.Errorf("%s: want %q, got %v", test.description, ErrInvalidHistogram, err)
+		}
+	}
+
+	h := mustHistogram(t, []float64{0, 1, 2}, []float64{0, 1})
+	if _, err := h.Mean(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := h.StandardDeviation(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	if _, err := h.MeanConfidenceIntervals(0.95); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+
+	h = mustHistogram(t, []float64{0, 1, 2}, []float64{0, 0})
+	if _, err := h.Quantile(0.5); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+
+	h = mustHistogram(t, []float64{0, 1, 2}, []float64{3, 1})
+	if _, err := h.MeanConfidenceIntervals(1.5); !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("want %q, got %v", ErrInvalidConfidence, err)
+	}
+}
@@ -0,0 +1,185 @@
+package httpapi
+
+import (
+	"github.com/alcortesm/sample"
+)
+
+type dataRequest struct {
+	Data       []float64 `json:"data"`
+	Confidence *float64  `json:"confidence"`
+}
+
+func confidence(c *float64) float64 {
+	if c == nil {
+		return DefaultConfidence
+	}
+	return *c
+}
+
+type summaryResponse struct {
+	N                 int        `json:"n"`
+	Mean              float64    `json:"mean"`
+	StandardDeviation float64    `json:"stddev"`
+	StandardError     float64    `json:"stderr"`
+	Confidence        float64    `json:"confidence"`
+	Interval          [2]float64 `json:"interval"`
+}
+
+func describe(decode func(interface{}) error) (interface{}, error) {
+	var req dataRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+
+	s, err := sample.Describe(req.Data, confidence(req.Confidence))
+	if err != nil {
+		return nil, err
+	}
+
+	return newSummaryResponse(s), nil
+}
+
+func newSummaryResponse(s sample.Summary) summaryResponse {
+	return summaryResponse{
+		N:                 s.N,
+		Mean:              s.Mean,
+		StandardDeviation: s.StandardDeviation,
+		StandardError:     s.StandardError,
+		Confidence:        s.Confidence,
+		Interval:          s.Interval,
+	}
+}
+
+type intervalResponse struct {
+	Mean       float64    `json:"mean"`
+	Confidence float64    `json:"confidence"`
+	Interval   [2]float64 `json:"interval"`
+}
+
+func meanInterval(decode func(interface{}) error) (interface{}, error) {
+	var req dataRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+
+	c := confidence(req.Confidence)
+	interval, err := sample.MeanConfidenceIntervals(req.Data, c)
+	if err != nil {
+		return nil, err
+	}
+	mean, _ := sample.Mean(req.Data)
+
+	return intervalResponse{
+		Mean:       mean,
+		Confidence: c,
+		Interval:   interval,
+	}, nil
+}
+
+type quantilesRequest struct {
+	Data          []float64 `json:"data"`
+	Probabilities []float64 `json:"probabilities"`
+}
+
+type quantile struct {
+	P     float64 `json:"p"`
+	Value float64 `json:"value"`
+}
+
+type quantilesResponse struct {
+	Quantiles []quantile `json:"quantiles"`
+}
+
+func quantiles(decode func(interface{}) error) (interface{}, error) {
+	var req quantilesRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+
+	ecdf, err := sample.NewECDF(req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := quantilesResponse{Quantiles: []quantile{}}
+	for _, p := range req.Probabilities {
+		v, err := ecdf.Quantile(p)
+		if err != nil {
+			return nil, err
+		}
+		resp.Quantiles = append(resp.Quantiles, quantile{P: p, Value: v})
+	}
+
+	return resp, nil
+}
+
+type compareRequest struct {
+	A          []float64 `json:"a"`
+	B          []float64 `json:"b"`
+	Confidence *float64  `json:"confidence"`
+}
+
+type effectSize struct {
+	Estimate float64    `json:"estimate"`
+	Interval [2]float64 `json:"interval"`
+}
+
+// The p-values are those of Welch's t-test and of the Mann–Whitney U
+// test; Significant refers to the former.
+type compareResponse struct {
+	A                 summaryResponse `json:"a"`
+	B                 summaryResponse `json:"b"`
+	Difference        float64         `json:"difference"`
+	WelchPValue       float64         `json:"welch_p_value"`
+	MannWhitneyPValue float64         `json:"mann_whitney_p_value"`
+	Significant       bool            `json:"significant"`
+	CohensD           effectSize      `json:"cohens_d"`
+	VarghaDelaneyA12  effectSize      `json:"vargha_delaney_a12"`
+}
+
+func compare(decode func(interface{}) error) (interface{}, error) {
+	var req compareRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	c := confidence(req.Confidence)
+
+	var summaries [2]summaryResponse
+	for i, data := range [][]float64{req.A, req.B} {
+		s, err := sample.Describe(data, c)
+		if err != nil {
+			return nil, err
+		}
+		summaries[i] = newSummaryResponse(s)
+	}
+
+	// Yuen's test without trimming is Welch's t-test, which only needs
+	// one of the samples to vary
+	welch, err := sample.Yuen(req.A, req.B, 0.0, c)
+	if err != nil {
+		return nil, err
+	}
+	mannWhitney, err := sample.KruskalWallis([][]float64{req.A, req.B})
+	if err != nil {
+		return nil, err
+	}
+	d, err := sample.CohensD(req.A, req.B, c)
+	if err != nil {
+		return nil, err
+	}
+	a12, err := sample.VarghaDelaneyA12(req.A, req.B, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareResponse{
+		A:                 summaries[0],
+		B:                 summaries[1],
+		Difference:        summaries[0].Mean - summaries[1].Mean,
+		WelchPValue:       welch.PValue,
+		MannWhitneyPValue: mannWhitney.PValue,
+		Significant:       welch.PValue < 1.0-c,
+		CohensD:           effectSize{d.Estimate, d.Interval},
+		VarghaDelaneyA12:  effectSize{a12.Estimate, a12.Interval},
+	}, nil
+}
@@ -0,0 +1,174 @@
+/*
+Package httpapi exposes the sample package over HTTP, as a JSON API, so
+tools not written in Go can use it.
+
+NewHandler returns an http.Handler that can be mounted in an existing
+server. Every endpoint accepts POST requests with a JSON body and
+answers with a JSON body:
+
+	POST /describe
+		{"data": [1, 2, 3], "confidence": 0.95}
+		returns the summary of the data, as sample.Describe.
+	POST /mean/interval
+		{"data": [1, 2, 3], "confidence": 0.95}
+		returns the mean and its confidence intervals.
+	POST /quantiles
+		{"data": [1, 2, 3], "probabilities": [0.5, 0.9]}
+		returns the quantiles of the empirical distribution of the data.
+	POST /compare
+		{"a": [1, 2, 3], "b": [4, 5, 6], "confidence": 0.95}
+		compares the means of two samples.
+
+The confidence is optional, 0.95 is used if it is missing.
+
+Errors are reported with a 4xx status code, or 500 for unexpected ones,
+and a body like:
+
+	{"error": {"code": "sample_too_small", "message": "too few sample points"}}
+*/
+package httpapi // import "github.com/alcortesm/sample/httpapi"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/alcortesm/sample"
+)
+
+// DefaultConfidence is the confidence level used when a request does
+// not have one.
+const DefaultConfidence = 0.95
+
+// MaxBodySize is the maximum size of a request body, in bytes.
+const MaxBodySize = 10 << 20
+
+// NewHandler returns a handler serving the API endpoints.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/describe", endpoint(describe))
+	mux.Handle("/mean/interval", endpoint(meanInterval))
+	mux.Handle("/quantiles", endpoint(quantiles))
+	mux.Handle("/compare", endpoint(compare))
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &apiError{
+			status:  http.StatusNotFound,
+			Code:    "not_found",
+			Message: "unknown endpoint " + r.URL.Path,
+		})
+	}))
+	return mux
+}
+
+// An endpoint decodes the JSON body of the request into a value of its
+// request type, computes a result and returns it or an error.
+type endpoint func(decode func(interface{}) error) (interface{}, error)
+
+func (e endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, &apiError{
+			status:  http.StatusMethodNotAllowed,
+			Code:    "method_not_allowed",
+			Message: "only POST is allowed",
+		})
+		return
+	}
+
+	decode := func(v interface{}) error {
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(v); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return &apiError{
+					status:  http.StatusRequestEntityTooLarge,
+					Code:    "body_too_large",
+					Message: err.Error(),
+				}
+			}
+			return &apiError{
+				status:  http.StatusBadRequest,
+				Code:    "invalid_json",
+				Message: err.Error(),
+			}
+		}
+		return nil
+	}
+
+	result, err := e(decode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+type apiError struct {
+	status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+// Maps the errors of the sample package to status codes and error
+// codes; unknown errors are internal server errors.
+func toAPIError(err error) *apiError {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	e := &apiError{Message: err.Error()}
+	switch {
+	case errors.Is(err, sample.ErrSampleTooSmall):
+		e.status, e.Code = http.StatusUnprocessableEntity, "sample_too_small"
+	case errors.Is(err, sample.ErrZeroVariance):
+		e.status, e.Code = http.StatusUnprocessableEntity, "zero_variance"
+	case errors.Is(err, sample.ErrInvalidConfidence):
+		e.status, e.Code = http.StatusBadRequest, "invalid_confidence"
+	case errors.Is(err, sample.ErrUnsupportedConfidence):
+		e.status, e.Code = http.StatusUnprocessableEntity, "unsupported_confidence"
+	case errors.Is(err, sample.ErrInvalidProbability):
+		e.status, e.Code = http.StatusBadRequest, "invalid_probability"
+	default:
+		e.status, e.Code = http.StatusInternalServerError, "internal"
+	}
+	return e
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e := toAPIError(err)
+	writeJSON(w, e.status, struct {
+		Error *apiError `json:"error"`
+	}{e})
+}
+
+// Encodes v before writing the status, so results that cannot be
+// encoded, like those with infinite or NaN values, are reported as
+// errors instead of as an empty successful response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		e := &apiError{
+			status:  http.StatusInternalServerError,
+			Code:    "internal",
+			Message: err.Error(),
+		}
+		var unsupported *json.UnsupportedValueError
+		if errors.As(err, &unsupported) {
+			e.status, e.Code = http.StatusUnprocessableEntity, "unrepresentable_result"
+		}
+		writeError(w, e)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,281 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const tolerance = 1e-4
+
+func equals(a, b float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// posts body to the given path of a new handler and decodes the JSON
+// response into v, returning the status code.
+func post(t *testing.T, path, body string, v interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("wrong content type: %q", got)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("cannot decode response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+	var got summaryResponse
+	status := post(t, "/describe", `{"data": [2, 3, 5, 6, 9], "confidence": 0.95}`, &got)
+	if status != http.StatusOK {
+		t.Fatalf("wrong status: %d", status)
+	}
+	if got.N != 5 || !equals(got.Mean, 5) || !equals(got.StandardDeviation, 2.7386) ||
+		!equals(got.StandardError, 1.2247) || got.Confidence != 0.95 {
+		t.Errorf("wrong summary: %+v", got)
+	}
+	if !equals(got.Interval[0], 1.6001) || !equals(got.Interval[1], 8.3999) {
+		t.Errorf("wrong interval: %f", got.Interval)
+	}
+}
+
+func TestMeanIntervalDefaultConfidence(t *testing.T) {
+	t.Parallel()
+	var got intervalResponse
+	status := post(t, "/mean/interval", `{"data": [2, 3, 5, 6, 9]}`, &got)
+	if status != http.StatusOK {
+		t.Fatalf("wrong status: %d", status)
+	}
+	if got.Confidence != DefaultConfidence || !equals(got.Mean, 5) ||
+		!equals(got.Interval[0], 1.6001) || !equals(got.Interval[1], 8.3999) {
+		t.Errorf("wrong response: %+v", got)
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	t.Parallel()
+	var got quantilesResponse
+	status := post(t, "/quantiles",
+		`{"data": [9, 2, 6, 3, 5], "probabilities": [0, 0.5, 0.9]}`, &got)
+	if status != http.StatusOK {
+		t.Fatalf("wrong status: %d", status)
+	}
+	want := []quantile{{0, 2}, {0.5, 5}, {0.9, 9}}
+	if len(got.Quantiles) != len(want) {
+		t.Fatalf("want %v, got %v", want, got.Quantiles)
+	}
+	for i := range want {
+		if got.Quantiles[i] != want[i] {
+			t.Errorf("want %v, got %v", want, got.Quantiles)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	var got compareResponse
+	status := post(t, "/compare",
+		`{"a": [2, 3, 5, 6, 9], "b": [12, 13, 15, 16, 19], "confidence": 0.99}`, &got)
+	if status != http.StatusOK {
+		t.Fatalf("wrong status: %d", status)
+	}
+	if got.A.N != 5 || got.B.N != 5 || !equals(got.A.Mean, 5) || !equals(got.B.Mean, 15) {
+		t.Errorf("wrong summaries: %+v, %+v", got.A, got.B)
+	}
+	if !equals(got.Difference, -10) {
+		t.Errorf("wrong difference: %f", got.Difference)
+	}
+	if !got.Significant || got.WelchPValue >= 0.01 {
+		t.Errorf("want a significant difference, got p=%f", got.WelchPValue)
+	}
+	if got.MannWhitneyPValue >= 0.05 {
+		t.Errorf("wrong Mann–Whitney p-value: %f", got.MannWhitneyPValue)
+	}
+	if got.CohensD.Estimate >= 0 ||
+		got.CohensD.Interval[0] > got.CohensD.Estimate ||
+		got.CohensD.Interval[1] < got.CohensD.Estimate {
+		t.Errorf("wrong Cohen's d: %+v", got.CohensD)
+	}
+	if got.VarghaDelaneyA12.Estimate != 0 {
+		t.Errorf("wrong A12: %+v", got.VarghaDelaneyA12)
+	}
+}
+
+// Welch's t-test is defined when only one of the samples is constant,
+// which is common in benchmarks, like with allocations per operation.
+func TestCompareConstantSample(t *testing.T) {
+	t.Parallel()
+	var got compareResponse
+	status := post(t, "/compare", `{"a": [1, 1, 1, 1], "b": [2, 3, 4, 5]}`, &got)
+	if status != http.StatusOK {
+		t.Fatalf("wrong status: %d", status)
+	}
+	// t = -3.873 with 3 degrees of freedom
+	if !equals(got.WelchPValue, 0.0305) || !got.Significant {
+		t.Errorf("wrong Welch's p-value: %f", got.WelchPValue)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		method      string
+		path        string
+		body        string
+		status      int
+		code        string
+	}{
+		{
+			description: "sample too small",
+			path:        "/describe",
+			body:        `{"data": [1]}`,
+			status:      http.StatusUnprocessableEntity,
+			code:        "sample_too_small",
+		}, {
+			description: "empty sample",
+			path:        "/mean/interval",
+			body:        `{}`,
+			status:      http.StatusUnprocessableEntity,
+			code:        "sample_too_small",
+		}, {
+			description: "invalid confidence",
+			path:        "/describe",
+			body:        `{"data": [1, 2], "confidence": 1.5}`,
+			status:      http.StatusBadRequest,
+			code:        "invalid_confidence",
+		}, {
+			description: "zero confidence",
+			path:        "/compare",
+			body:        `{"a": [1, 2], "b": [3, 4], "confidence": 0}`,
+			status:      http.StatusBadRequest,
+			code:        "invalid_confidence",
+		}, {
+			description: "unsupported confidence",
+			path:        "/mean/interval",
+			body:        `{"data": [1, 2], "confidence": 0.9995}`,
+			status:      http.StatusUnprocessableEntity,
+			code:        "unsupported_confidence",
+		}, {
+			description: "infinite result",
+			path:        "/describe",
+			body:        `{"data": [1e308, -1e308]}`,
+			status:      http.StatusUnprocessableEntity,
+			code:        "unrepresentable_result",
+		}, {
+			description: "body too large",
+			path:        "/describe",
+			body:        `{"data": [` + strings.Repeat("1, ", MaxBodySize/3) + `1]}`,
+			status:      http.StatusRequestEntityTooLarge,
+			code:        "body_too_large",
+		}, {
+			description: "invalid probability",
+			path:        "/quantiles",
+			body:        `{"data": [1, 2], "probabilities": [1.1]}`,
+			status:      http.StatusBadRequest,
+			code:        "invalid_probability",
+		}, {
+			description: "zero variance",
+			path:        "/compare",
+			body:        `{"a": [1, 1], "b": [1, 1]}`,
+			status:      http.StatusUnprocessableEntity,
+			code:        "zero_variance",
+		}, {
+			description: "malformed JSON",
+			path:        "/describe",
+			body:        `{"data": [1, 2`,
+			status:      http.StatusBadRequest,
+			code:        "invalid_json",
+		}, {
+			description: "wrong type",
+			path:        "/describe",
+			body:        `{"data": ["a"]}`,
+			status:      http.StatusBadRequest,
+			code:        "invalid_json",
+		}, {
+			description: "unknown field",
+			path:        "/describe",
+			body:        `{"data": [1, 2], "confidnce": 0.9}`,
+			status:      http.StatusBadRequest,
+			code:        "invalid_json",
+		}, {
+			description: "wrong method",
+			method:      http.MethodGet,
+			path:        "/describe",
+			status:      http.StatusMethodNotAllowed,
+			code:        "method_not_allowed",
+		}, {
+			description: "unknown endpoint",
+			path:        "/median",
+			body:        `{"data": [1, 2]}`,
+			status:      http.StatusNotFound,
+			code:        "not_found",
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, test.path, strings.NewReader(test.body))
+			rec := httptest.NewRecorder()
+			NewHandler().ServeHTTP(rec, req)
+
+			if rec.Code != test.status {
+				t.Errorf("want status %d, got %d", test.status, rec.Code)
+			}
+			var body struct {
+				Error struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("cannot decode response %q: %v", rec.Body.String(), err)
+			}
+			if body.Error.Code != test.code {
+				t.Errorf("want code %q, got %q", test.code, body.Error.Code)
+			}
+			if body.Error.Message == "" {
+				t.Error("empty error message")
+			}
+		})
+	}
+}
+
+func TestMountedUnderPrefix(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.Handle("/stats/", http.StripPrefix("/stats", NewHandler()))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/stats/describe", "application/json",
+		strings.NewReader(`{"data": [2, 3, 5, 6, 9]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wrong status: %d", resp.StatusCode)
+	}
+	var got summaryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.N != 5 || !equals(got.Mean, 5) {
+		t.Errorf("wrong summary: %+v", got)
+	}
+}
@@ -0,0 +1,126 @@
+package sample
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Until are the stopping conditions of Measure.
+//
+// Measure stops as soon as the half-width of the confidence intervals
+// of the mean, relative to the absolute value of the mean, is less than
+// or equal to RelativeHalfWidth, which must be positive. MaxSamples and
+// MaxDuration limit the number of samples taken and the time spent
+// taking them, a zero value means no limit.
+//
+// As the target is relative to the mean, it is never reached for a mean
+// of 0 unless all the samples are equal. Without limits and without a
+// deadline in the context, Measure runs forever in that case.
+type Until struct {
+	RelativeHalfWidth float64
+	Confidence        float64
+	MinSamples        int
+	MaxSamples        int
+	MaxDuration       time.Duration
+}
+
+// Measurement is the outcome of Measure.
+//
+// Converged tells if the relative half-width target was reached, it is
+// false if Measure stopped because of the MaxSamples or MaxDuration
+// limits. Mean and Interval are zero if less than 2 samples were taken.
+type Measurement struct {
+	Mean      float64
+	Interval  [2]float64
+	Samples   []float64
+	Converged bool
+}
+
+// N returns the number of samples taken.
+func (m Measurement) N() int {
+	return len(m.Samples)
+}
+
+// Measure calls f repeatedly, collecting the values it returns as
+// sample points, until the confidence intervals of their mean are
+// narrow enough or a limit in until is reached. At least
+// until.MinSamples samples are taken, and never less than 2.
+//
+// If f returns an error, Measure stops and returns it along with the
+// samples taken so far.
+//
+// If ctx is done, Measure stops and returns ctx.Err() along with the
+// samples taken so far.
+//
+// If until.Confidence is not in the ]0, 1[ it returns
+// ErrInvalidConfidence, and if it is bigger than 0.999,
+// ErrUnsupportedConfidence.
+//
+// If until.RelativeHalfWidth is not positive, it returns
+// ErrInvalidTarget.
+func Measure(ctx context.Context, f func() (float64, error), until Until) (Measurement, error) {
+	// fail early on confidence levels the intervals do not support
+	if _, err := studentTwoSidedCriticalValue(1, until.Confidence); err != nil {
+		return Measurement{}, err
+	}
+	if !(until.RelativeHalfWidth > 0.0) {
+		return Measurement{}, ErrInvalidTarget
+	}
+
+	minSamples := until.MinSamples
+	if minSamples < 2 {
+		minSamples = 2
+	}
+
+	var deadline time.Time
+	if until.MaxDuration > 0 {
+		deadline = time.Now().Add(until.MaxDuration)
+	}
+
+	m := Measurement{}
+	// updated with each sample, to not go over all of them every time
+	var moments moments
+	for {
+		if err := ctx.Err(); err != nil {
+			return m, err
+		}
+
+		v, err := f()
+		if err != nil {
+			return m, err
+		}
+		m.Samples = append(m.Samples, v)
+
+		moments.add(v)
+		if moments.n >= 2 {
+			m.Mean = moments.mean
+			m.Interval, err = moments.meanConfidenceIntervals("Measure", until.Confidence)
+			if err != nil {
+				return m, err
+			}
+		}
+
+		if len(m.Samples) >= minSamples &&
+			relativeHalfWidth(m.Mean, m.Interval) <= until.RelativeHalfWidth {
+			m.Converged = true
+			return m, nil
+		}
+
+		if until.MaxSamples > 0 && len(m.Samples) >= until.MaxSamples {
+			return m, nil
+		}
+
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return m, nil
+		}
+	}
+}
+
+func relativeHalfWidth(mean float64, interval [2]float64) float64 {
+	halfWidth := (interval[1] - interval[0]) / 2.0
+	if halfWidth == 0.0 {
+		return 0.0
+	}
+	return halfWidth / math.Abs(mean)
+}
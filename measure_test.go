@@ -0,0 +1,243 @@
+package sample
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// returns a function that cycles through the given values
+func cycle(values ...float64) func() (float64, error) {
+	i := 0
+	return func() (float64, error) {
+		v := values[i%len(values)]
+		i++
+		return v, nil
+	}
+}
+
+func TestMeasureConverges(t *testing.T) {
+	t.Parallel()
+	got, err := Measure(context.Background(), cycle(10, 10.1), Until{
+		RelativeHalfWidth: 0.001,
+		Confidence:        0.95,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Converged {
+		t.Errorf("unexpected non-convergence after %d samples", got.N())
+	}
+	if got.Interval[0] > 10.05 || got.Interval[1] < 10.05 {
+		t.Errorf("interval %f does not contain the mean 10.05", got.Interval)
+	}
+	if hw := relativeHalfWidth(got.Mean, got.Interval); hw > 0.001 {
+		t.Errorf("relative half-width too big: %f", hw)
+	}
+
+	// one less sample must not be enough
+	previous, err := MeanConfidenceIntervals(got.Samples[:got.N()-1], 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mean, _ := Mean(got.Samples[:got.N()-1])
+	if relativeHalfWidth(mean, previous) <= 0.001 {
+		t.Errorf("took more samples than needed: %d", got.N())
+	}
+}
+
+func TestMeasureMinSamples(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		minSamples int
+		want       int
+	}{
+		{minSamples: 0, want: 2},
+		{minSamples: 1, want: 2},
+		{minSamples: 2, want: 2},
+		{minSamples: 7, want: 7},
+	} {
+		got, err := Measure(context.Background(), cycle(3), Until{
+			RelativeHalfWidth: 0.1,
+			Confidence:        0.95,
+			MinSamples:        test.minSamples,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Converged || got.N() != test.want {
+			t.Errorf("MinSamples %d: want %d converged samples, got %d (converged=%t)",
+				test.minSamples, test.want, got.N(), got.Converged)
+		}
+		if got.Mean != 3 {
+			t.Errorf("MinSamples %d: want mean 3, got %f", test.minSamples, got.Mean)
+		}
+	}
+}
+
+func TestMeasureMaxSamples(t *testing.T) {
+	t.Parallel()
+	got, err := Measure(context.Background(), cycle(1, 100), Until{
+		RelativeHalfWidth: 1e-9,
+		Confidence:        0.95,
+		MaxSamples:        25,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Converged {
+		t.Error("unexpected convergence")
+	}
+	if got.N() != 25 {
+		t.Errorf("want 25 samples, got %d", got.N())
+	}
+}
+
+func TestMeasureMaxDuration(t *testing.T) {
+	t.Parallel()
+	f := cycle(1, 100)
+	slow := func() (float64, error) {
+		time.Sleep(time.Millisecond)
+		return f()
+	}
+	start := time.Now()
+	got, err := Measure(context.Background(), slow, Until{
+		RelativeHalfWidth: 1e-9,
+		Confidence:        0.95,
+		MaxDuration:       20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Converged {
+		t.Error("unexpected convergence")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took too long: %s", elapsed)
+	}
+	if got.N() < 1 {
+		t.Errorf("want some samples, got %d", got.N())
+	}
+}
+
+func TestMeasureContextCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := cycle(1, 100)
+	calls := 0
+	g := func() (float64, error) {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return f()
+	}
+	got, err := Measure(ctx, g, Until{
+		RelativeHalfWidth: 1e-9,
+		Confidence:        0.95,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want %q, got %v", context.Canceled, err)
+	}
+	if got.N() != 3 {
+		t.Errorf("want 3 samples, got %d", got.N())
+	}
+}
+
+func TestMeasureCallbackError(t *testing.T) {
+	t.Parallel()
+	want := errors.New("boom")
+	calls := 0
+	f := func() (float64, error) {
+		calls++
+		if calls == 4 {
+			return 0, want
+		}
+		return float64(calls), nil
+	}
+	got, err := Measure(context.Background(), f, Until{
+		RelativeHalfWidth: 1e-9,
+		Confidence:        0.95,
+	})
+	if !errors.Is(err, want) {
+		t.Fatalf("want %q, got %v", want, err)
+	}
+	if got.N() != 3 {
+		t.Errorf("want 3 samples, got %d", got.N())
+	}
+}
+
+func TestMeasureInvalidConfidence(t *testing.T) {
+	t.Parallel()
+	_, err := Measure(context.Background(), cycle(1), Until{Confidence: 1})
+	if !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("want %q, got %v", ErrInvalidConfidence, err)
+	}
+}
+
+// Targets that can never be reached used to make Measure run forever.
+func TestMeasureInvalidTarget(t *testing.T) {
+	t.Parallel()
+	for _, target := range []float64{0, -0.1, math.NaN()} {
+		calls := 0
+		f := func() (float64, error) {
+			calls++
+			return 1, nil
+		}
+		got, err := Measure(context.Background(), f, Until{
+			RelativeHalfWidth: target,
+			Confidence:        0.95,
+		})
+		if !errors.Is(err, ErrInvalidTarget) {
+			t.Errorf("%v: want %q, got %v", target, ErrInvalidTarget, err)
+		}
+		if got.Converged || calls != 0 {
+			t.Errorf("%v: want no samples taken, got %+v after %d calls", target, got, calls)
+		}
+	}
+}
+
+// Valid confidence levels beyond the Student-t table used to give a
+// zero interval that was reported as converged.
+func TestMeasureUnsupportedConfidence(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	f := func() (float64, error) {
+		calls++
+		return float64(calls), nil
+	}
+	got, err := Measure(context.Background(), f, Until{
+		RelativeHalfWidth: 0.1,
+		Confidence:        0.9995,
+	})
+	if !errors.Is(err, ErrUnsupportedConfidence) {
+		t.Errorf("want %q, got %v", ErrUnsupportedConfidence, err)
+	}
+	if got.Converged || calls != 0 {
+		t.Errorf("want no samples taken, got %+v after %d calls", got, calls)
+	}
+}
+
+// The running mean and intervals are the ones of the samples taken.
+func TestMeasureLongRun(t *testing.T) {
+	t.Parallel()
+	got, err := Measure(context.Background(), cycle(1e6, 1e6+1, 1e6+5), Until{
+		RelativeHalfWidth: 1e-9,
+		Confidence:        0.99,
+		MaxSamples:        20000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Converged || got.N() != 20000 {
+		t.Fatalf("want 20000 samples without converging, got %d, %t", got.N(), got.Converged)
+	}
+	mean, _ := Mean(got.Samples)
+	interval, _ := MeanConfidenceIntervals(got.Samples, 0.99)
+	if !equals(got.Mean, mean, 1e-6) || !pairEquals(got.Interval, interval, 1e-6) {
+		t.Errorf("want %f and %f, got %f and %f", mean, interval, got.Mean, got.Interval)
+	}
+}
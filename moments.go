@@ -0,0 +1,65 @@
+package sample
+
+import "math"
+
+// Accumulates the number, the mean and the sum of squared deviations
+// from the mean of a set of sample points with Welford's algorithm,
+// which supports removing sample points and merging two sets.
+type moments struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+func (m *moments) add(x float64) {
+	m.n++
+	delta := x - m.mean
+	m.mean += delta / float64(m.n)
+	m.m2 += delta * (x - m.mean)
+}
+
+// Removes a sample point previously added.
+func (m *moments) remove(x float64) {
+	m.n--
+	if m.n == 0 {
+		*m = moments{}
+		return
+	}
+	delta := x - m.mean
+	m.mean -= delta / float64(m.n)
+	m.m2 = math.Max(0.0, m.m2-delta*(x-m.mean))
+}
+
+// Adds all the sample points of other, with the parallel algorithm of
+// Chan, Golub and LeVeque.
+func (m *moments) merge(other moments) {
+	if other.n == 0 {
+		return
+	}
+	n := m.n + other.n
+	delta := other.mean - m.mean
+	m.mean += delta * float64(other.n) / float64(n)
+	m.m2 += other.m2 + delta*delta*float64(m.n)*float64(other.n)/float64(n)
+	m.n = n
+}
+
+// Returns the sample-based unbiased estimation of the variance of the
+// population, the n must be at least 2.
+func (m *moments) variance() float64 {
+	return m.m2 / float64(m.n-1)
+}
+
+// Returns the confidence intervals of the mean, like
+// MeanConfidenceIntervals; fn is the function reported in the sample
+// size errors.
+func (m *moments) meanConfidenceIntervals(fn string, confidence float64) ([2]float64, error) {
+	if err := checkSampleSize(fn, m.n, 2); err != nil {
+		return [2]float64{}, err
+	}
+	tinv, err := studentTwoSidedCriticalValue(int64(m.n-1), confidence)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	margin := tinv * math.Sqrt(m.variance()/float64(m.n))
+	return [2]float64{m.mean - margin, m.mean + margin}, nil
+}
@@ -0,0 +1,358 @@
+package sample
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Outlier is a sample point detected as an outlier: its index in the
+// sample, its value, and why it was detected, with the thresholds used,
+// so the detection can be audited and reproduced.
+type Outlier struct {
+	Index  int
+	Value  float64
+	Reason string
+}
+
+// Sorts the outliers by index.
+func sortOutliers(outliers []Outlier) []Outlier {
+	sort.Slice(outliers, func(i, j int) bool {
+		return outliers[i].Index < outliers[j].Index
+	})
+	return outliers
+}
+
+// TukeyFences detects the sample points outside Tukey's fences, below
+// Q1 - k IQR or above Q3 + k IQR, where Q1 and Q3 are the first and
+// third quartiles, interpolating linearly between sample points, and IQR
+// is the interquartile range. The usual values of k are 1.5 for
+// outliers and 3 for far out ones. It does not assume any shape of the
+// population.
+//
+// The outliers are returned in the order of the data.
+//
+// If k is not positive, it returns ErrInvalidTuning.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+func TukeyFences(data []float64, k float64) ([]Outlier, error) {
+	if !(k > 0.0) {
+		return nil, ErrInvalidTuning
+	}
+	if err := checkSampleSize("TukeyFences", len(data), 2); err != nil {
+		return nil, err
+	}
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+
+	q1 := interpolatedQuantile(sorted, 0.25)
+	q3 := interpolatedQuantile(sorted, 0.75)
+	lower := q1 - k*(q3-q1)
+	upper := q3 + k*(q3-q1)
+
+	outliers := []Outlier{}
+	for i, x := range data {
+		switch {
+		case x < lower:
+			outliers = append(outliers, Outlier{i, x,
+				fmt.Sprintf("below the lower fence %g (Q1 - %g IQR)", lower, k)})
+		case x > upper:
+			outliers = append(outliers, Outlier{i, x,
+				fmt.Sprintf("above the upper fence %g (Q3 + %g IQR)", upper, k)})
+		}
+	}
+	return outliers, nil
+}
+
+// ModifiedZScores detects the sample points with a modified z-score of
+// Iglewicz and Hoaglin (1993) bigger than the threshold in absolute
+// value; 3.5 is the usual threshold. The modified z-score is the
+// deviation from the median in units of the median absolute deviation
+// made consistent with the standard deviation, see
+// MedianAbsoluteDeviation, so it is not inflated by the outliers
+// themselves.
+//
+// The outliers are returned in the order of the data.
+//
+// If the threshold is not positive, it returns ErrInvalidTuning.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+//
+// If the median absolute deviation is zero, it returns ErrZeroVariance.
+func ModifiedZScores(data []float64, threshold float64) ([]Outlier, error) {
+	if !(threshold > 0.0) {
+		return nil, ErrInvalidTuning
+	}
+	if err := checkSampleSize("ModifiedZScores", len(data), 2); err != nil {
+		return nil, err
+	}
+	m := median(data)
+	mad := medianAbsoluteDeviation(data, m)
+	if mad == 0.0 {
+		return nil, ErrZeroVariance
+	}
+
+	outliers := []Outlier{}
+	for i, x := range data {
+		z := normalMAD * (x - m) / mad
+		if math.Abs(z) > threshold {
+			outliers = append(outliers, Outlier{i, x,
+				fmt.Sprintf("modified z-score %.4g beyond ±%g", z, threshold)})
+		}
+	}
+	return outliers, nil
+}
+
+// The sample points left by the iterative detectors, with their indexes
+// in the original data.
+type remaining struct {
+	values  []float64
+	indexes []int
+}
+
+func newRemaining(data []float64) *remaining {
+	r := &remaining{
+		values:  make([]float64, len(data)),
+		indexes: make([]int, len(data)),
+	}
+	copy(r.values, data)
+	for i := range r.indexes {
+		r.indexes[i] = i
+	}
+	return r
+}
+
+// Returns the position of the sample point farthest from the mean and
+// its distance in standard deviations, which is 0 when they are all
+// equal.
+func (r *remaining) farthest() (int, float64) {
+	mean, _ := Mean(r.values)
+	sd, _ := StandardDeviation(r.values)
+	farthest, distance := 0, 0.0
+	for i, x := range r.values {
+		if d := math.Abs(x - mean); d > distance {
+			farthest, distance = i, d
+		}
+	}
+	if sd == 0.0 {
+		return farthest, 0.0
+	}
+	return farthest, distance / sd
+}
+
+// Removes the sample point at position i and returns it.
+func (r *remaining) remove(i int) (int, float64) {
+	index, value := r.indexes[i], r.values[i]
+	r.indexes = append(r.indexes[:i], r.indexes[i+1:]...)
+	r.values = append(r.values[:i], r.values[i+1:]...)
+	return index, value
+}
+
+// Grubbs detects outliers with Grubbs' test, assuming the rest of the
+// sample points come from a Normal population: it tests the null
+// hypothesis that the sample point farthest from the mean is not an
+// outlier, two-sided, at the given confidence level, and if it is
+// rejected removes that sample point and tests again, until it is not
+// rejected or less than 3 sample points are left.
+//
+// Several outliers can mask each other, so that the first test is not
+// rejected; GeneralizedESD does not have that problem.
+//
+// The outliers are returned in the order of the data.
+//
+// If the sample size is less than 3, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func Grubbs(data []float64, confidence float64) ([]Outlier, error) {
+	if err := checkSampleSize("Grubbs", len(data), 3); err != nil {
+		return nil, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	outliers := []Outlier{}
+	r := newRemaining(data)
+	for round := 1; len(r.values) >= 3; round++ {
+		i, g := r.farthest()
+		critical := esdCriticalValue(len(r.values), 1.0-confidence)
+		if g <= critical {
+			break
+		}
+		index, value := r.remove(i)
+		outliers = append(outliers, Outlier{index, value,
+			fmt.Sprintf("Grubbs statistic %.4g above the critical value %.4g in round %d",
+				g, critical, round)})
+	}
+	return sortOutliers(outliers), nil
+}
+
+// Returns the critical value of the two-sided Grubbs test for a sample
+// of size n at the significance level alpha, which is the one of the
+// generalized ESD test for the sample points left.
+func esdCriticalValue(n int, alpha float64) float64 {
+	nf := float64(n)
+	t := studentTQuantile(1.0-alpha/(2.0*nf), nf-2.0)
+	return (nf - 1.0) * t / math.Sqrt((nf-2.0+t*t)*nf)
+}
+
+// GeneralizedESD detects up to maxOutliers outliers with the
+// generalized extreme Studentized deviate test of Rosner (1983),
+// assuming the rest of the sample points come from a Normal population.
+// It removes the sample point farthest from the mean maxOutliers times,
+// computing the Grubbs statistic each time, and the outliers are the
+// sample points removed up to the last time the statistic was above its
+// critical value at the given confidence level; unlike Grubbs, outliers
+// do not mask each other.
+//
+// The outliers are returned in the order of the data.
+//
+// If maxOutliers is not positive, it returns ErrInvalidTuning.
+//
+// If the sample size is less than maxOutliers+2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func GeneralizedESD(data []float64, maxOutliers int, confidence float64) ([]Outlier, error) {
+	if maxOutliers < 1 {
+		return nil, ErrInvalidTuning
+	}
+	if err := checkSampleSize("GeneralizedESD", len(data), maxOutliers+2); err != nil {
+		return nil, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	type step struct {
+		index               int
+		value               float64
+		statistic, critical float64
+	}
+	steps := make([]step, 0, maxOutliers)
+	count := 0
+	r := newRemaining(data)
+	for len(steps) < maxOutliers {
+		i, statistic := r.farthest()
+		if statistic == 0.0 {
+			break
+		}
+		critical := esdCriticalValue(len(r.values), 1.0-confidence)
+		if statistic > critical {
+			count = len(steps) + 1
+		}
+		index, value := r.remove(i)
+		steps = append(steps, step{index, value, statistic, critical})
+	}
+
+	outliers := make([]Outlier, count)
+	for i, s := range steps[:count] {
+		outliers[i] = Outlier{s.index, s.value,
+			fmt.Sprintf("step %d of %d, statistic %.4g and critical value %.4g",
+				i+1, count, s.statistic, s.critical)}
+	}
+	return sortOutliers(outliers), nil
+}
+
+// Chauvenet detects outliers with Chauvenet's criterion, assuming the
+// sample comes from a Normal population: a sample point is an outlier
+// when the expected number of sample points at least as far from the
+// mean, in a sample of the same size, is less than 0.5. The mean and
+// standard deviation are the ones of the whole sample, and the
+// criterion is applied only once.
+//
+// The outliers are returned in the order of the data.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+func Chauvenet(data []float64) ([]Outlier, error) {
+	if err := checkSampleSize("Chauvenet", len(data), 2); err != nil {
+		return nil, err
+	}
+	mean, _ := Mean(data)
+	sd, _ := StandardDeviation(data)
+
+	outliers := []Outlier{}
+	if sd == 0.0 {
+		return outliers, nil
+	}
+	n := float64(len(data))
+	for i, x := range data {
+		z := math.Abs(x-mean) / sd
+		expected := n * 2.0 * normalCDF(-z)
+		if expected < 0.5 {
+			outliers = append(outliers, Outlier{i, x,
+				fmt.Sprintf("%.4g standard deviations from the mean, expected %.3g such sample points",
+					z, expected)})
+		}
+	}
+	return outliers, nil
+}
+
+// OutlierReport records the outliers removed from a sample by
+// RemoveOutliers.
+type OutlierReport struct {
+	N       int // sample points before removing the outliers
+	Removed []Outlier
+}
+
+// String returns a line with the number of sample points removed,
+// followed by a line per outlier with its index, value and reason.
+func (r OutlierReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "removed %d of %d sample points", len(r.Removed), r.N)
+	for _, o := range r.Removed {
+		fmt.Fprintf(&b, "\n[%d] %g: %s", o.Index, o.Value, o.Reason)
+	}
+	return b.String()
+}
+
+// RemoveOutliers returns a copy of the data without the outliers found
+// by detect, keeping the order of the rest of the sample points, and a
+// report of the outliers removed. The detect function is usually a
+// closure around one of the detectors of the package, for example:
+//
+//	cleaned, report, err := RemoveOutliers(data, func(d []float64) ([]Outlier, error) {
+//		return TukeyFences(d, 1.5)
+//	})
+//
+// An outlier found more than once is removed, and reported, once.
+//
+// If detect returns an error, it returns that error. If an outlier has
+// an index outside of the data, it returns ErrInvalidOutlier.
+func RemoveOutliers(
+	data []float64,
+	detect func([]float64) ([]Outlier, error),
+) ([]float64, OutlierReport, error) {
+	outliers, err := detect(data)
+	if err != nil {
+		return nil, OutlierReport{}, err
+	}
+
+	removed := make([]bool, len(data))
+	var unique []Outlier
+	for _, o := range outliers {
+		if o.Index < 0 || o.Index >= len(data) {
+			return nil, OutlierReport{}, fmt.Errorf("%w: %d, the sample has %d points",
+				ErrInvalidOutlier, o.Index, len(data))
+		}
+		if !removed[o.Index] {
+			removed[o.Index] = true
+			unique = append(unique, o)
+		}
+	}
+	cleaned := make([]float64, 0, len(data)-len(unique))
+	for i, x := range data {
+		if !removed[i] {
+			cleaned = append(cleaned, x)
+		}
+	}
+
+	return cleaned, OutlierReport{
+		N:       len(data),
+		Removed: sortOutliers(unique),
+	}, nil
+}
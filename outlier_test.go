@@ -0,0 +1,212 @@
+package sample
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Returns the indexes of the outliers.
+func outlierIndexes(outliers []Outlier) []int {
+	indexes := []int{}
+	for _, o := range outliers {
+		indexes = append(indexes, o.Index)
+	}
+	return indexes
+}
+
+func sameIndexes(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOutlierDetectors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		detect func([]float64) ([]Outlier, error)
+		want   []int
+		reason string // in the reason of the first outlier
+	}{
+		{"Tukey", func(d []float64) ([]Outlier, error) {
+			return TukeyFences(d, 1.5)
+		}, []int{7}, "above the upper fence 12.2375 (Q3 + 1.5 IQR)"},
+		{"Tukey far out", func(d []float64) ([]Outlier, error) {
+			return TukeyFences(d, 3.0)
+		}, []int{7}, "above the upper fence 13.55 (Q3 + 3 IQR)"},
+		{"modified z", func(d []float64) ([]Outlier, error) {
+			return ModifiedZScores(d, 3.5)
+		}, []int{7}, "modified z-score 33.25 beyond ±3.5"},
+		{"Grubbs", func(d []float64) ([]Outlier, error) {
+			return Grubbs(d, 0.95)
+		}, []int{7}, "Grubbs statistic 3.161 above the critical value 2.412 in round 1"},
+		{"generalized ESD", func(d []float64) ([]Outlier, error) {
+			return GeneralizedESD(d, 3, 0.95)
+		}, []int{7}, "step 1 of 1, statistic 3.161 and critical value 2.412"},
+		{"Chauvenet", Chauvenet, []int{7}, "3.161 standard deviations from the mean"},
+	} {
+		outliers, err := test.detect(withOutlier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := outlierIndexes(outliers); !sameIndexes(got, test.want) {
+			t.Errorf("%s: want outliers %v, got %v", test.name, test.want, got)
+			continue
+		}
+		if outliers[0].Value != withOutlier[outliers[0].Index] ||
+			!strings.Contains(outliers[0].Reason, test.reason) {
+			t.Errorf("%s: want a reason with %q, got %+v", test.name, test.reason, outliers[0])
+		}
+	}
+}
+
+// The example of Rosner (1983) in the NIST/SEMATECH e-Handbook of
+// Statistical Methods, with 3 outliers that mask each other.
+var rosner = []float64{
+	-0.25, 0.68, 0.94, 1.15, 1.20, 1.26, 1.26, 1.34, 1.38, 1.43, 1.49,
+	1.49, 1.55, 1.56, 1.58, 1.65, 1.69, 1.70, 1.76, 1.77, 1.81, 1.91,
+	1.94, 1.96, 1.99, 2.06, 2.09, 2.10, 2.14, 2.15, 2.23, 2.24, 2.26,
+	2.35, 2.37, 2.40, 2.47, 2.54, 2.62, 2.64, 2.90, 2.92, 2.92, 2.93,
+	3.21, 3.26, 3.30, 3.59, 3.68, 4.30, 4.64, 5.34, 5.42, 6.01,
+}
+
+func TestGeneralizedESDMasking(t *testing.T) {
+	t.Parallel()
+	outliers, err := GeneralizedESD(rosner, 10, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := outlierIndexes(outliers), []int{51, 52, 53}; !sameIndexes(got, want) {
+		t.Fatalf("wrong outliers: want %v, got %v", want, got)
+	}
+	// the statistics and critical values of the handbook, which
+	// truncates them to 3 decimals
+	for i, reason := range []string{
+		"step 3 of 3, statistic 3.179 and critical value 3.144",
+		"step 2 of 3, statistic 2.943 and critical value 3.151",
+		"step 1 of 3, statistic 3.119 and critical value 3.159",
+	} {
+		if outliers[i].Reason != reason {
+			t.Errorf("wrong reason: want %q, got %q", reason, outliers[i].Reason)
+		}
+	}
+
+	// the first outlier is masked for Grubbs' test
+	outliers, err = Grubbs(rosner, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outliers) != 0 {
+		t.Errorf("want no outliers, got %v", outliers)
+	}
+}
+
+func TestRemoveOutliers(t *testing.T) {
+	t.Parallel()
+	cleaned, report, err := RemoveOutliers(rosner, func(d []float64) ([]Outlier, error) {
+		return GeneralizedESD(d, 10, 0.95)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cleaned) != len(rosner)-3 {
+		t.Fatalf("wrong number of sample points: want %d, got %d", len(rosner)-3, len(cleaned))
+	}
+	for i := range cleaned {
+		if cleaned[i] != rosner[i] {
+			t.Fatalf("wrong sample points: want %v, got %v", rosner[:51], cleaned)
+		}
+	}
+
+	want := `removed 3 of 54 sample points
+[51] 5.34: step 3 of 3, statistic 3.179 and critical value 3.144
+[52] 5.42: step 2 of 3, statistic 2.943 and critical value 3.151
+[53] 6.01: step 1 of 3, statistic 3.119 and critical value 3.159`
+	if got := report.String(); got != want {
+		t.Errorf("wrong report:\nwant %s\n got %s", want, got)
+	}
+
+	// the cleaned sample can be used with the other functions
+	if _, err := MeanConfidenceIntervals(cleaned, 0.95); err != nil {
+		t.Error(err)
+	}
+
+	_, _, err = RemoveOutliers(rosner, func(d []float64) ([]Outlier, error) {
+		return TukeyFences(d, -1.0)
+	})
+	if !errors.Is(err, ErrInvalidTuning) {
+		t.Errorf("wrong error from the detector: %v", err)
+	}
+}
+
+func TestRemoveOutliersFromCustomDetectors(t *testing.T) {
+	t.Parallel()
+	data := []float64{1.0, 2.0, 3.0}
+	cleaned, report, err := RemoveOutliers(data, func(d []float64) ([]Outlier, error) {
+		return []Outlier{{Index: 2, Value: 3.0}, {Index: 0, Value: 1.0}, {Index: 2, Value: 3.0}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cleaned) != 1 || cleaned[0] != 2.0 {
+		t.Errorf("wrong sample points: %v", cleaned)
+	}
+	if len(report.Removed) != 2 || report.Removed[0].Index != 0 || report.Removed[1].Index != 2 {
+		t.Errorf("wrong outliers removed: %+v", report.Removed)
+	}
+
+	for _, index := range []int{-1, 3} {
+		_, _, err := RemoveOutliers(data, func(d []float64) ([]Outlier, error) {
+			return []Outlier{{Index: index}}, nil
+		})
+		if !errors.Is(err, ErrInvalidOutlier) {
+			t.Errorf("index %d: want %q, got %v", index, ErrInvalidOutlier, err)
+		}
+	}
+}
+
+func TestOutlierErrors(t *testing.T) {
+	t.Parallel()
+	if _, err := ModifiedZScores(withOutlier, 0.0); !errors.Is(err, ErrInvalidTuning) {
+		t.Errorf("wrong error with threshold 0: %v", err)
+	}
+	if _, err := ModifiedZScores([]float64{1.0, 1.0, 1.0, 9.0}, 3.5); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("wrong error with zero MAD: %v", err)
+	}
+	if _, err := Grubbs([]float64{1.0, 2.0}, 0.95); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with 2 sample points: %v", err)
+	}
+	if _, err := Grubbs(withOutlier, 0.0); !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("wrong error with confidence 0: %v", err)
+	}
+	if _, err := GeneralizedESD(withOutlier, 0, 0.95); !errors.Is(err, ErrInvalidTuning) {
+		t.Errorf("wrong error with no outliers: %v", err)
+	}
+	if _, err := GeneralizedESD(withOutlier, 11, 0.95); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with too many outliers: %v", err)
+	}
+	if _, err := Chauvenet([]float64{1.0}); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with 1 sample point: %v", err)
+	}
+
+	// equal sample points have no outliers
+	constant := []float64{2.0, 2.0, 2.0, 2.0}
+	for name, detect := range map[string]func([]float64) ([]Outlier, error){
+		"Tukey":     func(d []float64) ([]Outlier, error) { return TukeyFences(d, 1.5) },
+		"Grubbs":    func(d []float64) ([]Outlier, error) { return Grubbs(d, 0.95) },
+		"ESD":       func(d []float64) ([]Outlier, error) { return GeneralizedESD(d, 2, 0.95) },
+		"Chauvenet": Chauvenet,
+	} {
+		outliers, err := detect(constant)
+		if err != nil || len(outliers) != 0 {
+			t.Errorf("%s: want no outliers, got %v, %v", name, outliers, err)
+		}
+	}
+}
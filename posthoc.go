@@ -0,0 +1,138 @@
+package sample
+
+import "math"
+
+// PairwiseComparison is the comparison of the means of two groups, I
+// and J, with I < J, from a post-hoc test.
+//
+// Difference is the mean of group I minus the mean of group J and
+// Interval are its simultaneous confidence intervals. PValue is
+// adjusted for the multiple comparisons performed.
+type PairwiseComparison struct {
+	I          int
+	J          int
+	Difference float64
+	Interval   [2]float64
+	PValue     float64
+}
+
+// TukeyHSD performs Tukey's honestly significant difference test (the
+// Tukey–Kramer method for unequal group sizes), comparing the means of
+// every pair of groups. It assumes the populations are Normal and have
+// equal variances, this is, the same assumptions as OneWayANOVA.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func TukeyHSD(groups [][]float64, confidence float64) ([]PairwiseComparison, error) {
+	summaries, err := summarizeGroups("TukeyHSD", groups)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	within := 0.0
+	for _, s := range summaries {
+		total += s.N
+		within += float64(s.N-1) * s.StandardDeviation * s.StandardDeviation
+	}
+	k := float64(len(summaries))
+	df := float64(total) - k
+	mse := within / df
+	critical := studentizedRangeQuantile(confidence, k, df)
+
+	return pairwise(summaries, func(a, b GroupSummary) (float64, float64, float64) {
+		se := math.Sqrt(mse / 2.0 * (1.0/float64(a.N) + 1.0/float64(b.N)))
+		return se, critical, df
+	}), nil
+}
+
+// GamesHowell performs the Games–Howell test, comparing the means of
+// every pair of groups. Unlike TukeyHSD, it does not assume the
+// populations have equal variances, using Welch's degrees of freedom
+// for each pair instead.
+//
+// The studentized range distribution is poorly approximated for less
+// than 2 degrees of freedom, so the results are not reliable for very
+// small groups.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the standard deviations of both groups in a pair are zero, it
+// returns ErrZeroVariance.
+func GamesHowell(groups [][]float64, confidence float64) ([]PairwiseComparison, error) {
+	summaries, err := summarizeGroups("GamesHowell", groups)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		for j := i + 1; j < len(summaries); j++ {
+			if summaries[i].StandardDeviation == 0.0 &&
+				summaries[j].StandardDeviation == 0.0 {
+				return nil, ErrZeroVariance
+			}
+		}
+	}
+
+	k := float64(len(summaries))
+	return pairwise(summaries, func(a, b GroupSummary) (float64, float64, float64) {
+		va := a.StandardDeviation * a.StandardDeviation / float64(a.N)
+		vb := b.StandardDeviation * b.StandardDeviation / float64(b.N)
+		se := math.Sqrt((va + vb) / 2.0)
+		df := (va + vb) * (va + vb) /
+			(va*va/float64(a.N-1) + vb*vb/float64(b.N-1))
+		return se, studentizedRangeQuantile(confidence, k, df), df
+	}), nil
+}
+
+// Compares every pair of groups, using f to get the standard error of
+// their difference (scaled for the studentized range), the critical
+// value for the intervals and the degrees of freedom of the test.
+func pairwise(
+	summaries []GroupSummary,
+	f func(a, b GroupSummary) (se, critical, df float64),
+) []PairwiseComparison {
+	k := float64(len(summaries))
+	comparisons := []PairwiseComparison{}
+	for i := range summaries {
+		for j := i + 1; j < len(summaries); j++ {
+			se, critical, df := f(summaries[i], summaries[j])
+			diff := summaries[i].Mean - summaries[j].Mean
+			margin := critical * se
+
+			pValue := 0.0
+			if se > 0.0 {
+				q := math.Abs(diff) / se
+				pValue = 1.0 - studentizedRangeCDF(q, k, df)
+			} else if diff == 0.0 {
+				pValue = 1.0
+			}
+
+			comparisons = append(comparisons, PairwiseComparison{
+				I:          i,
+				J:          j,
+				Difference: diff,
+				Interval:   [2]float64{diff - margin, diff + margin},
+				PValue:     math.Max(pValue, 0.0),
+			})
+		}
+	}
+	return comparisons
+}
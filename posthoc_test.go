@@ -0,0 +1,115 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+func checkPairwiseComparisons(t *testing.T, got, want []PairwiseComparison) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("wrong number of comparisons: want %d, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.I != w.I || g.J != w.J {
+			t.Errorf("comparison %d: wrong groups: want (%d, %d), got (%d, %d)",
+				i, w.I, w.J, g.I, g.J)
+		}
+		if !equals(g.Difference, w.Difference, tolerance) {
+			t.Errorf("comparison %d: wrong difference: want %f, got %f",
+				i, w.Difference, g.Difference)
+		}
+		if !pairEquals(g.Interval, w.Interval, tolerance) {
+			t.Errorf("comparison %d: wrong interval: want %f, got %f",
+				i, w.Interval, g.Interval)
+		}
+		if !equals(g.PValue, w.PValue, tolerance) {
+			t.Errorf("comparison %d: wrong p-value: want %f, got %f",
+				i, w.PValue, g.PValue)
+		}
+	}
+}
+
+func TestTukeyHSD(t *testing.T) {
+	t.Parallel()
+	got, err := TukeyHSD(anovaGroups, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPairwiseComparisons(t, got, []PairwiseComparison{
+		{I: 0, J: 1, Difference: -4, Interval: [2]float64{-7.1930, -0.8070}, PValue: 0.01391},
+		{I: 0, J: 2, Difference: -5, Interval: [2]float64{-8.1930, -1.8070}, PValue: 0.00273},
+		{I: 1, J: 2, Difference: -1, Interval: [2]float64{-4.1930, 2.1930}, PValue: 0.70066},
+	})
+}
+
+func TestGamesHowell(t *testing.T) {
+	t.Parallel()
+	got, err := GamesHowell(anovaGroups, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPairwiseComparisons(t, got, []PairwiseComparison{
+		{I: 0, J: 1, Difference: -4, Interval: [2]float64{-7.1863, -0.8137}, PValue: 0.01612},
+		{I: 0, J: 2, Difference: -5, Interval: [2]float64{-8.3608, -1.6392}, PValue: 0.00609},
+		{I: 1, J: 2, Difference: -1, Interval: [2]float64{-4.6126, 2.6126}, PValue: 0.73499},
+	})
+}
+
+func TestPostHocErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		groups      [][]float64
+		confidence  float64
+		want        error
+	}{
+		{
+			description: "one group",
+			groups:      [][]float64{{1, 2, 3}},
+			confidence:  0.95,
+			want:        ErrTooFewGroups,
+		}, {
+			description: "small group",
+			groups:      [][]float64{{1, 2, 3}, {1}},
+			confidence:  0.95,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "zero confidence",
+			groups:      anovaGroups,
+			confidence:  0,
+			want:        ErrInvalidConfidence,
+		}, {
+			description: "confidence of one",
+			groups:      anovaGroups,
+			confidence:  1,
+			want:        ErrInvalidConfidence,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			for name, f := range map[string]func([][]float64, float64) ([]PairwiseComparison, error){
+				"TukeyHSD":    TukeyHSD,
+				"GamesHowell": GamesHowell,
+			} {
+				_, err := f(test.groups, test.confidence)
+				if err == nil {
+					t.Fatalf("%s: unexpected success", name)
+				}
+				if !errors.Is(err, test.want) {
+					t.Errorf("%s: want %q, got %q", name, test.want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGamesHowellZeroVariance(t *testing.T) {
+	t.Parallel()
+	_, err := GamesHowell([][]float64{{1, 1}, {2, 2}, {1, 2, 3}}, 0.95)
+	if !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("want %q, got %v", ErrZeroVariance, err)
+	}
+}
@@ -0,0 +1,218 @@
+package sample
+
+import "math"
+
+// Design is the layout of an experiment comparing means with a t-test.
+type Design int
+
+// OneSample compares the mean of a population with a fixed value.
+// Paired compares the means of two measurements taken on the same
+// subjects, which is a one-sample design on their differences.
+// TwoSample compares the means of two independent groups of the same
+// size and variance.
+const (
+	OneSample Design = iota
+	Paired
+	TwoSample
+)
+
+// Returns the degrees of freedom of the t statistic and the factor
+// that converts a standardized effect size into its noncentrality
+// parameter, for n sample points per group.
+func (d Design) parameters(n int) (df, factor float64, err error) {
+	fn := float64(n)
+	switch d {
+	case OneSample, Paired:
+		return fn - 1.0, math.Sqrt(fn), nil
+	case TwoSample:
+		return 2.0*fn - 2.0, math.Sqrt(fn / 2.0), nil
+	default:
+		return 0.0, 0.0, ErrUnknownDesign
+	}
+}
+
+// Power returns the probability of a two-sided t-test at the given
+// confidence level detecting a standardized effect of the given size,
+// with n sample points per group, computed from the noncentral t
+// distribution.
+//
+// The effect is the difference between the means in units of the
+// standard deviation of the population (Cohen's d); for the Paired
+// design the standard deviation is the one of the differences.
+//
+// If n is less than 2, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the design is not one of the Design constants, it returns
+// ErrUnknownDesign.
+func Power(design Design, effect float64, n int, confidence float64) (float64, error) {
+	if err := checkSampleSize("Power", n, 2); err != nil {
+		return 0.0, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return 0.0, err
+	}
+	df, factor, err := design.parameters(n)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return power(df, effect*factor, confidence), nil
+}
+
+func power(df, ncp, confidence float64) float64 {
+	critical := studentTQuantile(1.0-(1.0-confidence)/2.0, df)
+	return 1.0 - noncentralTCDF(critical, df, ncp) +
+		noncentralTCDF(-critical, df, ncp)
+}
+
+// SampleSize returns the smallest number of sample points per group a
+// two-sided t-test at the given confidence level needs to detect a
+// standardized effect of the given size with at least the given power.
+// See Power for the meaning of the effect.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the power is not in the ]1-confidence, 1[ range, it returns
+// ErrInvalidProbability.
+//
+// If the effect is zero, it returns ErrInvalidEffect.
+//
+// If the design is not one of the Design constants, it returns
+// ErrUnknownDesign.
+//
+// If more than math.MaxInt32 sample points per group are needed, it
+// returns ErrSampleSizeTooLarge.
+func SampleSize(design Design, effect, confidence, target float64) (int, error) {
+	if err := checkConfidence(confidence); err != nil {
+		return 0, err
+	}
+	if !(target > 1.0-confidence && target < 1.0) {
+		return 0, ErrInvalidProbability
+	}
+	if effect == 0.0 || math.IsNaN(effect) {
+		return 0, ErrInvalidEffect
+	}
+	if _, _, err := design.parameters(2); err != nil {
+		return 0, err
+	}
+
+	// start from the Normal approximation and walk to the exact answer
+	z := normalQuantile(1.0-(1.0-confidence)/2.0) + normalQuantile(target)
+	guess := z * z / (effect * effect)
+	if design == TwoSample {
+		guess *= 2.0
+	}
+
+	return smallestN(guess, func(n int) bool {
+		df, factor, _ := design.parameters(n)
+		return power(df, effect*factor, confidence) >= target
+	})
+}
+
+// SampleSizeForMargin returns the smallest number of sample points per
+// group for the confidence intervals of the mean (or of the difference
+// between the means, for the TwoSample design) to have at most the
+// given margin of error, this is, half-width, at the given confidence
+// level. The sd argument is an estimate of the standard deviation of
+// the population; for the Paired design, of the differences.
+//
+// The margin of error is calculated as in MeanConfidenceIntervals, but
+// using exact Student-t critical values instead of the t-table.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If sd or margin are not positive, it returns ErrInvalidEffect.
+//
+// If the design is not one of the Design constants, it returns
+// ErrUnknownDesign.
+//
+// If more than math.MaxInt32 sample points per group are needed, it
+// returns ErrSampleSizeTooLarge.
+func SampleSizeForMargin(design Design, sd, margin, confidence float64) (int, error) {
+	if err := checkConfidence(confidence); err != nil {
+		return 0, err
+	}
+	if !(sd > 0.0 && margin > 0.0) {
+		return 0, ErrInvalidEffect
+	}
+	if _, _, err := design.parameters(2); err != nil {
+		return 0, err
+	}
+
+	p := 1.0 - (1.0-confidence)/2.0
+	z := normalQuantile(p) * sd / margin
+	guess := z * z
+	if design == TwoSample {
+		guess *= 2.0
+	}
+
+	return smallestN(guess, func(n int) bool {
+		df, factor, _ := design.parameters(n)
+		return studentTQuantile(p, df)*sd/factor <= margin
+	})
+}
+
+// Returns the smallest n >= 2 for which ok is true, searching around
+// guess: it doubles the distance to guess until it brackets the answer
+// and then bisects. The ok function must be monotonic in n. If the
+// guess, or the answer, is not below math.MaxInt32 it returns
+// ErrSampleSizeTooLarge.
+func smallestN(guess float64, ok func(n int) bool) (int, error) {
+	if math.IsNaN(guess) || guess >= math.MaxInt32 {
+		return 0, ErrSampleSizeTooLarge
+	}
+	n := 2
+	if guess > 2.0 {
+		n = int(math.Ceil(guess))
+	}
+
+	// ok(hi) is true and ok(lo) is false, with 1 standing for the
+	// sizes below 2, that are never checked
+	var lo, hi int
+	if ok(n) {
+		hi = n
+		for step := 1; ; step *= 2 {
+			lo = hi - step
+			if lo < 2 {
+				lo = 1
+				break
+			}
+			if !ok(lo) {
+				break
+			}
+			hi = lo
+		}
+	} else {
+		lo = n
+		for step := 1; ; step *= 2 {
+			if lo >= math.MaxInt32-step {
+				hi = math.MaxInt32
+				if !ok(hi) {
+					return 0, ErrSampleSizeTooLarge
+				}
+				break
+			}
+			hi = lo + step
+			if ok(hi) {
+				break
+			}
+			lo = hi
+		}
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if ok(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, nil
+}
@@ -0,0 +1,193 @@
+package sample
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPower(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		design     Design
+		effect     float64
+		n          int
+		confidence float64
+		want       float64
+	}{
+		{design: TwoSample, effect: 0.5, n: 64, confidence: 0.95, want: 0.80146},
+		{design: OneSample, effect: 0.5, n: 34, confidence: 0.95, want: 0.80778},
+		{design: Paired, effect: -0.5, n: 34, confidence: 0.95, want: 0.80778},
+		{design: OneSample, effect: 0, n: 10, confidence: 0.95, want: 0.05},
+		{design: TwoSample, effect: 0, n: 10, confidence: 0.99, want: 0.01},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v, %v, %v",
+			test.design, test.effect, test.n, test.confidence)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got, err := Power(test.design, test.effect, test.n, test.confidence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equals(got, test.want, tolerance) {
+				t.Errorf("want %f, got %f", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSampleSize(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		design     Design
+		effect     float64
+		confidence float64
+		power      float64
+		want       int
+	}{
+		{design: TwoSample, effect: 0.5, confidence: 0.95, power: 0.8, want: 64},
+		{design: TwoSample, effect: 0.8, confidence: 0.95, power: 0.9, want: 34},
+		{design: OneSample, effect: 0.5, confidence: 0.95, power: 0.8, want: 34},
+		{design: Paired, effect: -0.5, confidence: 0.95, power: 0.8, want: 34},
+		{design: OneSample, effect: 3, confidence: 0.95, power: 0.8, want: 4},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v, %v, %v",
+			test.design, test.effect, test.confidence, test.power)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got, err := SampleSize(test.design, test.effect, test.confidence, test.power)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("want %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSampleSizeForMargin(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		design     Design
+		sd         float64
+		margin     float64
+		confidence float64
+		want       int
+	}{
+		{design: OneSample, sd: 1, margin: 0.5, confidence: 0.95, want: 18},
+		{design: Paired, sd: 2, margin: 1, confidence: 0.95, want: 18},
+		{design: TwoSample, sd: 1, margin: 0.5, confidence: 0.95, want: 32},
+		{design: OneSample, sd: 1, margin: 100, confidence: 0.95, want: 2},
+		{design: OneSample, sd: 1, margin: 1e-3, confidence: 0.95, want: 3841462},
+	} {
+		test := test
+		description := fmt.Sprintf("%v, %v, %v, %v",
+			test.design, test.sd, test.margin, test.confidence)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got, err := SampleSizeForMargin(test.design, test.sd, test.margin, test.confidence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("want %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPowerErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		f           func() error
+		want        error
+	}{
+		{
+			description: "Power with small sample",
+			f: func() error {
+				_, err := Power(OneSample, 0.5, 1, 0.95)
+				return err
+			},
+			want: ErrSampleTooSmall,
+		}, {
+			description: "Power with invalid confidence",
+			f: func() error {
+				_, err := Power(OneSample, 0.5, 10, 1)
+				return err
+			},
+			want: ErrInvalidConfidence,
+		}, {
+			description: "Power with unknown design",
+			f: func() error {
+				_, err := Power(Design(-1), 0.5, 10, 0.95)
+				return err
+			},
+			want: ErrUnknownDesign,
+		}, {
+			description: "SampleSize with zero effect",
+			f: func() error {
+				_, err := SampleSize(TwoSample, 0, 0.95, 0.8)
+				return err
+			},
+			want: ErrInvalidEffect,
+		}, {
+			description: "SampleSize with power below alpha",
+			f: func() error {
+				_, err := SampleSize(TwoSample, 0.5, 0.95, 0.01)
+				return err
+			},
+			want: ErrInvalidProbability,
+		}, {
+			description: "SampleSize with invalid confidence",
+			f: func() error {
+				_, err := SampleSize(TwoSample, 0.5, 0, 0.8)
+				return err
+			},
+			want: ErrInvalidConfidence,
+		}, {
+			description: "SampleSize with tiny effect",
+			f: func() error {
+				_, err := SampleSize(TwoSample, 1e-5, 0.95, 0.8)
+				return err
+			},
+			want: ErrSampleSizeTooLarge,
+		}, {
+			description: "SampleSizeForMargin with zero margin",
+			f: func() error {
+				_, err := SampleSizeForMargin(OneSample, 1, 0, 0.95)
+				return err
+			},
+			want: ErrInvalidEffect,
+		}, {
+			description: "SampleSizeForMargin with tiny margin",
+			f: func() error {
+				_, err := SampleSizeForMargin(OneSample, 1, 1e-5, 0.95)
+				return err
+			},
+			want: ErrSampleSizeTooLarge,
+		}, {
+			description: "SampleSizeForMargin with unknown design",
+			f: func() error {
+				_, err := SampleSizeForMargin(Design(7), 1, 1, 0.95)
+				return err
+			},
+			want: ErrUnknownDesign,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			err := test.f()
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !errors.Is(err, test.want) {
+				t.Errorf("want %q, got %q", test.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package sample
+
+import "sort"
+
+// Returns the 1-based ranks of the values in data, in the same order as
+// data. Tied values get the average of the ranks they span.
+//
+// It also returns the sum of t^3-t over every group of t tied values,
+// the quantity most rank-based tests need for their tie correction.
+func ranks(data []float64) (r []float64, ties float64) {
+	index := make([]int, len(data))
+	for i := range index {
+		index[i] = i
+	}
+	sort.Slice(index, func(i, j int) bool {
+		return data[index[i]] < data[index[j]]
+	})
+
+	r = make([]float64, len(data))
+	for b := 0; b < len(index); {
+		e := b + 1
+		for e < len(index) && data[index[e]] == data[index[b]] {
+			e++
+		}
+
+		// positions b..e-1 have ranks b+1..e
+		avg := float64(b+1+e) / 2.0
+		for i := b; i < e; i++ {
+			r[index[i]] = avg
+		}
+
+		t := float64(e - b)
+		ties += t*t*t - t
+		b = e
+	}
+
+	return r, ties
+}
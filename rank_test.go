@@ -0,0 +1,52 @@
+package sample
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRanks(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		input    []float64
+		want     []float64
+		wantTies float64
+	}{
+		{
+			input:    []float64{},
+			want:     []float64{},
+			wantTies: 0,
+		}, {
+			input:    []float64{3, 1, 2},
+			want:     []float64{3, 1, 2},
+			wantTies: 0,
+		}, {
+			input:    []float64{1, 2, 2, 3},
+			want:     []float64{1, 2.5, 2.5, 4},
+			wantTies: 6,
+		}, {
+			input:    []float64{5, 5, 5, 1, 1},
+			want:     []float64{4, 4, 4, 1.5, 1.5},
+			wantTies: 30,
+		},
+	} {
+		test := test
+		description := fmt.Sprint(test.input)
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+			got, ties := ranks(test.input)
+			if len(got) != len(test.want) {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+			for i := range got {
+				if !equals(got[i], test.want[i], tolerance) {
+					t.Errorf("want %v, got %v", test.want, got)
+					break
+				}
+			}
+			if !equals(ties, test.wantTies, tolerance) {
+				t.Errorf("wrong ties: want %f, got %f", test.wantTies, ties)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package sample
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Recorder accumulates the sample points recorded by many goroutines,
+// to summarize them like Describe does. Its state is split in shards,
+// and goroutines running on different processors tend to use different
+// shards, so recording scales with the number of processors instead of
+// contending on a single lock; the shards are merged when reading.
+//
+// A Recorder is safe for concurrent use. The zero value is not usable,
+// use NewRecorder to create one.
+type Recorder struct {
+	shards []recorderShard
+	// indexes of shards; sync.Pool keeps a cache per processor, which
+	// gives each processor a shard of its own most of the time
+	hints sync.Pool
+	next  uint32 // index of the shard of the next hint
+}
+
+type recorderShard struct {
+	mu      sync.Mutex
+	moments moments
+	// keeps shards in different cache lines
+	_ [64]byte
+}
+
+// NewRecorder returns an empty recorder with one shard per processor
+// usable by the program, as reported by runtime.GOMAXPROCS.
+func NewRecorder() *Recorder {
+	r := &Recorder{
+		shards: make([]recorderShard, runtime.GOMAXPROCS(0)),
+	}
+	r.hints.New = func() interface{} {
+		i := int(atomic.AddUint32(&r.next, 1)-1) % len(r.shards)
+		return &i
+	}
+	return r
+}
+
+// Add records a sample point. NaNs are ignored.
+func (r *Recorder) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	hint := r.hints.Get().(*int)
+	s := &r.shards[*hint]
+	s.mu.Lock()
+	s.moments.add(x)
+	s.mu.Unlock()
+	r.hints.Put(hint)
+}
+
+// Returns the moments of all the shards.
+func (r *Recorder) merged() moments {
+	var m moments
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.mu.Lock()
+		m.merge(s.moments)
+		s.mu.Unlock()
+	}
+	return m
+}
+
+// N returns the number of sample points recorded.
+func (r *Recorder) N() int {
+	return r.merged().n
+}
+
+// Snapshot returns the summary of the sample points recorded, using the
+// given confidence level for the confidence intervals of the mean. The
+// shards are read one after another, so sample points recorded while
+// taking the snapshot may or may not be included.
+//
+// If less than 2 sample points have been recorded, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func (r *Recorder) Snapshot(confidence float64) (Summary, error) {
+	m := r.merged()
+	interval, err := m.meanConfidenceIntervals("Recorder.Snapshot", confidence)
+	if err != nil {
+		return Summary{}, err
+	}
+	sd := math.Sqrt(m.variance())
+	return Summary{
+		N:                 m.n,
+		Mean:              m.mean,
+		StandardDeviation: sd,
+		StandardError:     sd / math.Sqrt(float64(m.n)),
+		Confidence:        confidence,
+		Interval:          interval,
+	}, nil
+}
+
+// Reset discards all the sample points recorded. Like Snapshot, it
+// clears the shards one after another.
+func (r *Recorder) Reset() {
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.mu.Lock()
+		s.moments = moments{}
+		s.mu.Unlock()
+	}
+}
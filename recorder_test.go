@@ -0,0 +1,131 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	const (
+		goroutines = 64
+		perRoutine = 2000
+	)
+	r := NewRecorder()
+
+	// each goroutine records its own data, while others take snapshots
+	data := make([][]float64, goroutines)
+	for i := range data {
+		rng := rand.New(rand.NewSource(int64(i)))
+		data[i] = make([]float64, perRoutine)
+		for j := range data[i] {
+			data[i][j] = 1e3 + 10.0*rng.NormFloat64()
+		}
+	}
+	var wg sync.WaitGroup
+	for i := range data {
+		wg.Add(1)
+		go func(data []float64) {
+			defer wg.Done()
+			for _, x := range data {
+				r.Add(x)
+			}
+			r.Add(math.NaN())
+		}(data[i])
+	}
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				summary, err := r.Snapshot(0.95)
+				if errors.Is(err, ErrSampleTooSmall) {
+					continue
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if summary.N > goroutines*perRoutine ||
+					!(summary.Interval[0] <= summary.Mean &&
+						summary.Mean <= summary.Interval[1]) {
+					t.Errorf("inconsistent snapshot: %+v", summary)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	all := []float64{}
+	for _, d := range data {
+		all = append(all, d...)
+	}
+	want, err := Describe(all, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Snapshot(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.N != want.N || r.N() != want.N ||
+		!equals(got.Mean, want.Mean, 1e-9) ||
+		!equals(got.StandardDeviation, want.StandardDeviation, 1e-9) ||
+		!equals(got.StandardError, want.StandardError, 1e-9) ||
+		!pairEquals(got.Interval, want.Interval, 1e-9) ||
+		got.Confidence != want.Confidence {
+		t.Errorf("wrong snapshot:\nwant %+v\n got %+v", want, got)
+	}
+}
+
+func TestRecorderReset(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder()
+	for _, x := range []float64{1.0, 2.0, 3.0} {
+		r.Add(x)
+	}
+	r.Reset()
+	if r.N() != 0 {
+		t.Errorf("wrong N after reset: %d", r.N())
+	}
+
+	data := []float64{1.1, 0.9, 1.1, 1.3, 1.0}
+	for _, x := range data {
+		r.Add(x)
+	}
+	got, err := r.Snapshot(0.99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Describe(data, 0.99)
+	if !equals(got.Mean, want.Mean, 1e-9) ||
+		!pairEquals(got.Interval, want.Interval, 1e-9) {
+		t.Errorf("wrong snapshot:\nwant %+v\n got %+v", want, got)
+	}
+}
+
+func TestRecorderErrors(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder()
+	r.Add(1.0)
+	if _, err := r.Snapshot(0.95); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with 1 sample point: %v", err)
+	}
+	r.Add(2.0)
+	if _, err := r.Snapshot(1.0); !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("wrong error with confidence 1: %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+package sample
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// Reservoir keeps a uniform random sample of fixed size of a stream of
+// sample points of unknown length: after adding n sample points, each
+// subset of size min(n, size) is equally likely to be its contents.
+// Its values can be passed to the functions of the package, like
+// MeanConfidenceIntervals or NewECDF, to analyse streams too long to
+// keep in memory.
+//
+// A Reservoir is not safe for concurrent use. The zero value is not
+// usable, use NewReservoir or NewSkipReservoir to create one.
+type Reservoir struct {
+	size   int
+	n      uint64
+	values []float64
+	rng    *rand.Rand
+
+	// state of Algorithm L
+	skipping bool
+	w        float64 // largest of the random keys of the values
+	skip     uint64  // sample points to skip before the next replacement
+}
+
+// NewReservoir returns an empty reservoir of the given size using
+// Algorithm R (Vitter, 1985), which draws a random number for each
+// sample point added. The seed initializes its source of randomness,
+// so reservoirs with the same seed fed with the same sample points have
+// the same contents.
+//
+// If size is not positive, it returns ErrInvalidReservoir.
+func NewReservoir(size int, seed int64) (*Reservoir, error) {
+	if size < 1 {
+		return nil, ErrInvalidReservoir
+	}
+	return &Reservoir{
+		size: size,
+		rng:  rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// NewSkipReservoir is like NewReservoir, but uses Algorithm L (Li,
+// 1994), which computes how many sample points to skip before the next
+// replacement, so it only draws random numbers for the sample points
+// that enter the reservoir; it is much faster for long streams.
+func NewSkipReservoir(size int, seed int64) (*Reservoir, error) {
+	r, err := NewReservoir(size, seed)
+	if err != nil {
+		return nil, err
+	}
+	r.skipping = true
+	return r, nil
+}
+
+// Returns a random number in ]0, 1].
+func open01(rng *rand.Rand) float64 {
+	return 1.0 - rng.Float64()
+}
+
+// Add adds a sample point. NaNs are ignored.
+func (r *Reservoir) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+
+	r.n++
+	if len(r.values) < r.size {
+		r.values = append(r.values, x)
+		if r.skipping && len(r.values) == r.size {
+			r.w = math.Exp(math.Log(open01(r.rng)) / float64(r.size))
+			r.nextSkip()
+		}
+		return
+	}
+
+	if !r.skipping {
+		if j := r.rng.Int63n(int64(r.n)); j < int64(r.size) {
+			r.values[j] = x
+		}
+		return
+	}
+
+	if r.skip > 0 {
+		r.skip--
+		return
+	}
+	r.values[r.rng.Intn(r.size)] = x
+	r.w *= math.Exp(math.Log(open01(r.rng)) / float64(r.size))
+	r.nextSkip()
+}
+
+// Draws the number of sample points Algorithm L skips.
+func (r *Reservoir) nextSkip() {
+	skip := math.Floor(math.Log(open01(r.rng)) / math.Log1p(-r.w))
+	if skip >= math.MaxUint64 || math.IsNaN(skip) {
+		r.skip = math.MaxUint64
+		return
+	}
+	r.skip = uint64(skip)
+}
+
+// Merge replaces the contents of the reservoir by a uniform random
+// sample of the sample points added to it and to other, as if they had
+// all been added to it; other is not modified. The reservoirs must have
+// been created with unrelated seeds, like ones drawn from another
+// source of randomness, or their samples will be correlated.
+//
+// If the reservoirs have different sizes, it returns
+// ErrInvalidReservoir.
+func (r *Reservoir) Merge(other *Reservoir) error {
+	if r.size != other.size {
+		return ErrInvalidReservoir
+	}
+	if other.n == 0 {
+		return nil
+	}
+
+	// the number of values taken from each reservoir follows the
+	// hypergeometric distribution
+	a := append([]float64(nil), r.values...)
+	b := append([]float64(nil), other.values...)
+	r.rng.Shuffle(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+	r.rng.Shuffle(len(b), func(i, j int) { b[i], b[j] = b[j], b[i] })
+	leftA, leftB := r.n, other.n
+	merged := make([]float64, 0, r.size)
+	for len(merged) < r.size && leftA+leftB > 0 {
+		if uint64(r.rng.Int63n(int64(leftA+leftB))) < leftA {
+			merged = append(merged, a[0])
+			a = a[1:]
+			leftA--
+		} else {
+			merged = append(merged, b[0])
+			b = b[1:]
+			leftB--
+		}
+	}
+	r.values = merged
+	r.n += other.n
+
+	if r.skipping && len(r.values) == r.size {
+		// the largest of the size smallest random keys of n sample
+		// points follows a Beta(size, n-size+1) distribution
+		x := gammaRand(r.rng, float64(r.size))
+		y := gammaRand(r.rng, float64(r.n-uint64(r.size)+1))
+		r.w = x / (x + y)
+		r.nextSkip()
+	}
+
+	return nil
+}
+
+// Returns a random number from a Gamma(shape, 1) distribution, with
+// shape at least 1, using the method of Marsaglia and Tsang (2000).
+func gammaRand(rng *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3.0
+	c := 1.0 / math.Sqrt(9.0*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1.0 + c*x
+		if v <= 0.0 {
+			continue
+		}
+		v = v * v * v
+		u := open01(rng)
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// N returns the number of sample points added to the reservoir.
+func (r *Reservoir) N() uint64 {
+	return r.n
+}
+
+// Values returns a copy of the contents of the reservoir, in no
+// particular order.
+func (r *Reservoir) Values() []float64 {
+	values := make([]float64, len(r.values))
+	copy(values, r.values)
+	return values
+}
+
+// WeightedReservoir keeps a weighted random sample of fixed size of a
+// stream of sample points of unknown length: it is drawn without
+// replacement, with probabilities proportional to the weights of the
+// sample points, using the random keys u^(1/w) of Efraimidis and
+// Spirakis (2006), where u is a uniform random number and w the weight:
+// the sample points with the biggest keys are kept.
+//
+// A WeightedReservoir is not safe for concurrent use. The zero value is
+// not usable, use NewWeightedReservoir or NewSkipWeightedReservoir to
+// create one.
+type WeightedReservoir struct {
+	size int
+	n    uint64
+	// min-heap of keys, kept as log(u)/w to avoid underflows
+	items weightedItems
+	rng   *rand.Rand
+
+	// state of A-ExpJ
+	skipping bool
+	jump     float64 // weight to skip before the next replacement
+}
+
+type weightedItem struct {
+	value float64
+	key   float64
+}
+
+type weightedItems []weightedItem
+
+func (h weightedItems) Len() int            { return len(h) }
+func (h weightedItems) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h weightedItems) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *weightedItems) Push(x interface{}) { *h = append(*h, x.(weightedItem)) }
+func (h *weightedItems) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// NewWeightedReservoir returns an empty weighted reservoir of the given
+// size using the A-Res algorithm, which draws a random number for each
+// sample point added. The seed initializes its source of randomness.
+//
+// If size is not positive, it returns ErrInvalidReservoir.
+func NewWeightedReservoir(size int, seed int64) (*WeightedReservoir, error) {
+	if size < 1 {
+		return nil, ErrInvalidReservoir
+	}
+	return &WeightedReservoir{
+		size: size,
+		rng:  rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// NewSkipWeightedReservoir is like NewWeightedReservoir, but uses the
+// A-ExpJ algorithm, which computes how much weight to skip before the
+// next replacement, so it only draws random numbers for the sample
+// points that enter the reservoir.
+func NewSkipWeightedReservoir(size int, seed int64) (*WeightedReservoir, error) {
+	r, err := NewWeightedReservoir(size, seed)
+	if err != nil {
+		return nil, err
+	}
+	r.skipping = true
+	return r, nil
+}
+
+// Add adds a sample point with the given weight. Sample points with
+// zero weight and NaNs are ignored.
+//
+// If the weight is negative or not finite, it returns
+// ErrInvalidWeights.
+func (r *WeightedReservoir) Add(x, weight float64) error {
+	if !(weight >= 0.0) || math.IsInf(weight, 1) {
+		return ErrInvalidWeights
+	}
+	if weight == 0.0 || math.IsNaN(x) {
+		return nil
+	}
+
+	r.n++
+	if len(r.items) < r.size {
+		heap.Push(&r.items, weightedItem{
+			value: x,
+			key:   math.Log(open01(r.rng)) / weight,
+		})
+		if r.skipping && len(r.items) == r.size {
+			r.nextJump()
+		}
+		return nil
+	}
+
+	if !r.skipping {
+		key := math.Log(open01(r.rng)) / weight
+		if key > r.items[0].key {
+			r.items[0] = weightedItem{value: x, key: key}
+			heap.Fix(&r.items, 0)
+		}
+		return nil
+	}
+
+	r.jump -= weight
+	if r.jump > 0.0 {
+		return nil
+	}
+	// the key is uniform between the threshold and the biggest key
+	threshold := math.Exp(weight * r.items[0].key)
+	u := threshold + (1.0-threshold)*r.rng.Float64()
+	r.items[0] = weightedItem{value: x, key: math.Log(u) / weight}
+	heap.Fix(&r.items, 0)
+	r.nextJump()
+	return nil
+}
+
+// Draws the weight A-ExpJ skips: log(u) / log(T), where T is the
+// smallest key in the reservoir.
+func (r *WeightedReservoir) nextJump() {
+	r.jump = math.Log(open01(r.rng)) / r.items[0].key
+}
+
+// Merge replaces the contents of the reservoir by a weighted random
+// sample of the sample points added to it and to other, as if they had
+// all been added to it; other is not modified. As with
+// Reservoir.Merge, the reservoirs must have been created with unrelated
+// seeds.
+//
+// If the reservoirs have different sizes, it returns
+// ErrInvalidReservoir.
+func (r *WeightedReservoir) Merge(other *WeightedReservoir) error {
+	if r.size != other.size {
+		return ErrInvalidReservoir
+	}
+
+	// the sample points with the biggest keys of both
+	for _, item := range other.items {
+		if len(r.items) < r.size {
+			heap.Push(&r.items, item)
+			continue
+		}
+		if item.key > r.items[0].key {
+			r.items[0] = item
+			heap.Fix(&r.items, 0)
+		}
+	}
+	r.n += other.n
+
+	if r.skipping && len(r.items) == r.size {
+		r.nextJump()
+	}
+	return nil
+}
+
+// N returns the number of sample points with non-zero weight added to
+// the reservoir.
+func (r *WeightedReservoir) N() uint64 {
+	return r.n
+}
+
+// Values returns a copy of the contents of the reservoir, in no
+// particular order.
+func (r *WeightedReservoir) Values() []float64 {
+	values := make([]float64, len(r.items))
+	for i, item := range r.items {
+		values[i] = item.value
+	}
+	return values
+}
@@ -0,0 +1,286 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Checks each of the frequencies, from trials repetitions, is close to
+// its wanted probability, within 5 standard errors.
+func checkFrequencies(t *testing.T, counts []int, want []float64, trials int) {
+	t.Helper()
+	for i, c := range counts {
+		got := float64(c) / float64(trials)
+		se := math.Sqrt(want[i] * (1.0 - want[i]) / float64(trials))
+		if math.Abs(got-want[i]) > 5.0*se {
+			t.Errorf("wrong frequency of %d: want %f, got %f", i, want[i], got)
+		}
+	}
+}
+
+func TestReservoirUniform(t *testing.T) {
+	t.Parallel()
+	const (
+		size   = 10
+		n      = 100
+		trials = 5000
+	)
+	for _, test := range []struct {
+		name  string
+		new   func(int, int64) (*Reservoir, error)
+		merge bool
+	}{
+		{"R", NewReservoir, false},
+		{"L", NewSkipReservoir, false},
+		{"R merged", NewReservoir, true},
+		{"L merged", NewSkipReservoir, true},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			seeds := rand.New(rand.NewSource(1))
+			counts := make([]int, n)
+			for trial := 0; trial < trials; trial++ {
+				r, err := test.new(size, seeds.Int63())
+				if err != nil {
+					t.Fatal(err)
+				}
+				other, _ := test.new(size, seeds.Int63())
+				for i := 0; i < n; i++ {
+					if test.merge && i >= 30 {
+						other.Add(float64(i))
+						continue
+					}
+					r.Add(float64(i))
+				}
+				if test.merge {
+					if err := r.Merge(other); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if r.N() != n {
+					t.Fatalf("wrong N: want %d, got %d", n, r.N())
+				}
+				values := r.Values()
+				if len(values) != size {
+					t.Fatalf("wrong size: want %d, got %d", size, len(values))
+				}
+				for _, v := range values {
+					counts[int(v)]++
+				}
+			}
+			want := make([]float64, n)
+			for i := range want {
+				want[i] = float64(size) / float64(n)
+			}
+			checkFrequencies(t, counts, want, trials)
+		})
+	}
+}
+
+// Adding after a merge must keep the sample uniform, which for
+// Algorithm L depends on the threshold drawn by Merge.
+func TestSkipReservoirAddAfterMerge(t *testing.T) {
+	t.Parallel()
+	const (
+		size   = 5
+		n      = 60
+		trials = 5000
+	)
+	seeds := rand.New(rand.NewSource(1))
+	counts := make([]int, n)
+	for trial := 0; trial < trials; trial++ {
+		r, _ := NewSkipReservoir(size, seeds.Int63())
+		other, _ := NewSkipReservoir(size, seeds.Int63())
+		for i := 0; i < 20; i++ {
+			r.Add(float64(i))
+			other.Add(float64(20 + i))
+		}
+		if err := r.Merge(other); err != nil {
+			t.Fatal(err)
+		}
+		for i := 40; i < n; i++ {
+			r.Add(float64(i))
+		}
+		for _, v := range r.Values() {
+			counts[int(v)]++
+		}
+	}
+	want := make([]float64, n)
+	for i := range want {
+		want[i] = float64(size) / float64(n)
+	}
+	checkFrequencies(t, counts, want, trials)
+}
+
+func TestReservoirSmallStream(t *testing.T) {
+	t.Parallel()
+	r, err := NewSkipReservoir(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []float64{1.1, 0.9, math.NaN(), 1.1, 1.3, 1.0}
+	for _, x := range data {
+		r.Add(x)
+	}
+	if r.N() != 5 {
+		t.Errorf("wrong N: want 5, got %d", r.N())
+	}
+
+	// the contents can be used with the slice functions
+	interval, err := MeanConfidenceIntervals(r.Values(), 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [2]float64{0.896, 1.264}
+	if !pairEquals(interval, want, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want, interval)
+	}
+}
+
+func TestReservoirSeed(t *testing.T) {
+	t.Parallel()
+	for _, new := range []func(int, int64) (*Reservoir, error){
+		NewReservoir, NewSkipReservoir,
+	} {
+		a, _ := new(10, 42)
+		b, _ := new(10, 42)
+		for i := 0; i < 1000; i++ {
+			a.Add(float64(i))
+			b.Add(float64(i))
+		}
+		va, vb := a.Values(), b.Values()
+		for i := range va {
+			if va[i] != vb[i] {
+				t.Fatalf("different contents with the same seed: %v, %v", va, vb)
+			}
+		}
+	}
+}
+
+func TestReservoirErrors(t *testing.T) {
+	t.Parallel()
+	if _, err := NewReservoir(0, 1); !errors.Is(err, ErrInvalidReservoir) {
+		t.Errorf("wrong error for size 0: %v", err)
+	}
+	if _, err := NewSkipWeightedReservoir(-1, 1); !errors.Is(err, ErrInvalidReservoir) {
+		t.Errorf("wrong error for size -1: %v", err)
+	}
+
+	a, _ := NewReservoir(10, 1)
+	b, _ := NewReservoir(20, 1)
+	if err := a.Merge(b); !errors.Is(err, ErrInvalidReservoir) {
+		t.Errorf("wrong error merging different sizes: %v", err)
+	}
+	wa, _ := NewWeightedReservoir(10, 1)
+	wb, _ := NewWeightedReservoir(20, 1)
+	if err := wa.Merge(wb); !errors.Is(err, ErrInvalidReservoir) {
+		t.Errorf("wrong error merging different sizes: %v", err)
+	}
+
+	for _, w := range []float64{-1.0, math.NaN(), math.Inf(1)} {
+		if err := wa.Add(1.0, w); !errors.Is(err, ErrInvalidWeights) {
+			t.Errorf("wrong error for weight %f: %v", w, err)
+		}
+	}
+	if wa.N() != 0 {
+		t.Errorf("wrong N: want 0, got %d", wa.N())
+	}
+}
+
+func TestWeightedReservoir(t *testing.T) {
+	t.Parallel()
+	// with a size of 1, each sample point is chosen with a probability
+	// proportional to its weight
+	weights := []float64{1.0, 2.0, 3.0, 0.0, 4.0, 10.0}
+	want := []float64{0.05, 0.1, 0.15, 0.0, 0.2, 0.5}
+	const trials = 10000
+	for _, test := range []struct {
+		name  string
+		new   func(int, int64) (*WeightedReservoir, error)
+		merge bool
+	}{
+		{"A-Res", NewWeightedReservoir, false},
+		{"A-ExpJ", NewSkipWeightedReservoir, false},
+		{"A-Res merged", NewWeightedReservoir, true},
+		{"A-ExpJ merged", NewSkipWeightedReservoir, true},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			seeds := rand.New(rand.NewSource(1))
+			counts := make([]int, len(weights))
+			for trial := 0; trial < trials; trial++ {
+				r, err := test.new(1, seeds.Int63())
+				if err != nil {
+					t.Fatal(err)
+				}
+				other, _ := test.new(1, seeds.Int63())
+				for i, w := range weights {
+					target := r
+					if test.merge && i%2 == 1 {
+						target = other
+					}
+					if err := target.Add(float64(i), w); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if test.merge {
+					if err := r.Merge(other); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if r.N() != 5 {
+					t.Fatalf("wrong N: want 5, got %d", r.N())
+				}
+				counts[int(r.Values()[0])]++
+			}
+			checkFrequencies(t, counts, want, trials)
+		})
+	}
+}
+
+// Without replacement, the first of two sample points is chosen with a
+// probability of w1/W and the second with w2/(W-w1).
+func TestWeightedReservoirWithoutReplacement(t *testing.T) {
+	t.Parallel()
+	weights := []float64{1.0, 2.0, 7.0}
+	// P(i in sample) = sum over the first choices
+	want := make([]float64, len(weights))
+	for i, wi := range weights {
+		for j, wj := range weights {
+			if i == j {
+				continue
+			}
+			first := wi / 10.0
+			second := wj / 10.0 * wi / (10.0 - wj)
+			want[i] += first * wj / (10.0 - wi)
+			want[i] += second
+		}
+	}
+	const trials = 10000
+	for _, new := range []func(int, int64) (*WeightedReservoir, error){
+		NewWeightedReservoir, NewSkipWeightedReservoir,
+	} {
+		seeds := rand.New(rand.NewSource(1))
+		counts := make([]int, len(weights))
+		for trial := 0; trial < trials; trial++ {
+			r, _ := new(2, seeds.Int63())
+			for i, w := range weights {
+				_ = r.Add(float64(i), w)
+			}
+			// the stream goes on with points of negligible weight
+			for i := 0; i < 100; i++ {
+				_ = r.Add(-1.0, 1e-12)
+			}
+			for _, v := range r.Values() {
+				if v >= 0.0 {
+					counts[int(v)]++
+				}
+			}
+		}
+		checkFrequencies(t, counts, want, trials)
+	}
+}
@@ -0,0 +1,500 @@
+package sample
+
+import (
+	"math"
+	"sort"
+)
+
+// Returns a sorted copy of the data and the number of sample points to
+// trim from each end, checking the trimming proportion and that at
+// least need sample points are left; fn is the function reported in the
+// sample size errors.
+func trimmed(fn string, data []float64, trim float64, need int) ([]float64, int, error) {
+	if !(trim >= 0.0 && trim < 0.5) {
+		return nil, 0, ErrInvalidTrim
+	}
+	g := int(math.Floor(trim * float64(len(data))))
+	if err := checkSampleSize(fn, len(data), need+2*g); err != nil {
+		return nil, 0, err
+	}
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+	return sorted, g, nil
+}
+
+// Returns the mean of the sorted sample points without the g smallest
+// and the g biggest.
+func trimmedMean(sorted []float64, g int) float64 {
+	m, _ := Mean(sorted[g : len(sorted)-g])
+	return m
+}
+
+// Returns the sorted sample points with the g smallest replaced by the
+// next smallest and the g biggest by the next biggest.
+func winsorized(sorted []float64, g int) []float64 {
+	w := make([]float64, len(sorted))
+	copy(w, sorted)
+	n := len(w)
+	for i := 0; i < g; i++ {
+		w[i] = sorted[g]
+		w[n-1-i] = sorted[n-1-g]
+	}
+	return w
+}
+
+// Returns the standard error of the trimmed mean, from the winsorized
+// variance, and its degrees of freedom.
+func trimmedStandardError(sorted []float64, g int, trim float64) (float64, float64) {
+	n := float64(len(sorted))
+	sw, _ := StandardDeviation(winsorized(sorted, g))
+	return sw / ((1.0 - 2.0*trim) * math.Sqrt(n)), n - 2.0*float64(g) - 1.0
+}
+
+// TrimmedMean computes the mean of a population sample without the
+// given proportion of its smallest and of its biggest sample points,
+// rounded down to whole sample points: TrimmedMean(data, 0.2) ignores
+// the smallest and the biggest 20% of the sample points. A trim of 0
+// gives the mean and one close to 0.5 the median.
+//
+// If the trim is not in the [0, 0.5[ range, it returns ErrInvalidTrim.
+//
+// If no sample points are left after trimming, it returns
+// ErrSampleTooSmall.
+func TrimmedMean(data []float64, trim float64) (float64, error) {
+	sorted, g, err := trimmed("TrimmedMean", data, trim, 1)
+	if err != nil {
+		return 0.0, err
+	}
+	return trimmedMean(sorted, g), nil
+}
+
+// TrimmedStandardError returns the standard error of the trimmed mean,
+// computed from the winsorized variance of the sample.
+//
+// If the trim is not in the [0, 0.5[ range, it returns ErrInvalidTrim.
+//
+// If less than 2 sample points are left after trimming, it returns
+// ErrSampleTooSmall.
+func TrimmedStandardError(data []float64, trim float64) (float64, error) {
+	sorted, g, err := trimmed("TrimmedStandardError", data, trim, 2)
+	if err != nil {
+		return 0.0, err
+	}
+	se, _ := trimmedStandardError(sorted, g, trim)
+	return se, nil
+}
+
+// TrimmedMeanConfidenceIntervals calculates the Tukey–McLaughlin
+// confidence intervals of the trimmed mean of the population for the
+// given confidence level. They are the ones of MeanConfidenceIntervals
+// with the standard error of TrimmedStandardError and the degrees of
+// freedom of the sample points left after trimming, and are accurate
+// for a wider range of populations than the ones of the mean when the
+// trim is about 0.2.
+//
+// If the trim is not in the [0, 0.5[ range, it returns ErrInvalidTrim.
+//
+// If less than 2 sample points are left after trimming, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func TrimmedMeanConfidenceIntervals(data []float64, trim, confidence float64) ([2]float64, error) {
+	sorted, g, err := trimmed("TrimmedMeanConfidenceIntervals", data, trim, 2)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	se, df := trimmedStandardError(sorted, g, trim)
+	tinv, err := studentTwoSidedCriticalValue(int64(df), confidence)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	mean := trimmedMean(sorted, g)
+	margin := tinv * se
+	return [2]float64{mean - margin, mean + margin}, nil
+}
+
+// WinsorizedMean computes the mean of a population sample after
+// replacing the given proportion of its smallest sample points by the
+// smallest of the rest, and the same proportion of its biggest sample
+// points by the biggest of the rest.
+//
+// If the trim is not in the [0, 0.5[ range, it returns ErrInvalidTrim.
+//
+// If no sample points are left after trimming, it returns
+// ErrSampleTooSmall.
+func WinsorizedMean(data []float64, trim float64) (float64, error) {
+	sorted, g, err := trimmed("WinsorizedMean", data, trim, 1)
+	if err != nil {
+		return 0.0, err
+	}
+	m, _ := Mean(winsorized(sorted, g))
+	return m, nil
+}
+
+// WinsorizedMeanConfidenceIntervals calculates the confidence
+// intervals of the winsorized mean of the population for the given
+// confidence level, like TrimmedMeanConfidenceIntervals does for the
+// trimmed mean, with the same standard error and degrees of freedom.
+//
+// If the trim is not in the [0, 0.5[ range, it returns ErrInvalidTrim.
+//
+// If less than 2 sample points are left after trimming, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func WinsorizedMeanConfidenceIntervals(data []float64, trim, confidence float64) ([2]float64, error) {
+	sorted, g, err := trimmed("WinsorizedMeanConfidenceIntervals", data, trim, 2)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	se, df := trimmedStandardError(sorted, g, trim)
+	tinv, err := studentTwoSidedCriticalValue(int64(df), confidence)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	mean, _ := Mean(winsorized(sorted, g))
+	margin := tinv * se
+	return [2]float64{mean - margin, mean + margin}, nil
+}
+
+// YuenResult is the result of Yuen's test: the difference between the
+// trimmed means of two samples, its confidence intervals, and the
+// statistic, degrees of freedom and two-sided p-value of the test.
+type YuenResult struct {
+	Difference float64
+	Interval   [2]float64
+	Statistic  float64
+	DF         float64
+	PValue     float64
+}
+
+// Yuen performs Yuen's test, testing the null hypothesis that the
+// populations of a and b have the same trimmed mean, and calculates the
+// confidence intervals of the difference between the trimmed means of
+// a and b for the given confidence level. It is Welch's t-test on
+// trimmed means, with standard errors from the winsorized variances, so
+// it does not assume the populations have the same variance; with a
+// trim of 0 it is Welch's t-test.
+//
+// If the trim is not in the [0, 0.5[ range, it returns ErrInvalidTrim.
+//
+// If less than 2 sample points of a or b are left after trimming, it
+// returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the winsorized variances of both a and b are zero, it returns
+// ErrZeroVariance.
+func Yuen(a, b []float64, trim, confidence float64) (YuenResult, error) {
+	var means, ds, dfs [2]float64
+	for i, s := range [][]float64{a, b} {
+		sorted, g, err := trimmed("Yuen", s, trim, 2)
+		if err != nil {
+			return YuenResult{}, err
+		}
+		se, df := trimmedStandardError(sorted, g, trim)
+		means[i] = trimmedMean(sorted, g)
+		ds[i] = se * se
+		dfs[i] = df
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return YuenResult{}, err
+	}
+	if ds[0]+ds[1] == 0.0 {
+		return YuenResult{}, ErrZeroVariance
+	}
+
+	se := math.Sqrt(ds[0] + ds[1])
+	diff := means[0] - means[1]
+	t := diff / se
+	df := (ds[0] + ds[1]) * (ds[0] + ds[1]) /
+		(ds[0]*ds[0]/dfs[0] + ds[1]*ds[1]/dfs[1])
+	margin := studentTQuantile(1.0-(1.0-confidence)/2.0, df) * se
+
+	return YuenResult{
+		Difference: diff,
+		Interval:   [2]float64{diff - margin, diff + margin},
+		Statistic:  t,
+		DF:         df,
+		PValue:     2.0 * (1.0 - studentTCDF(math.Abs(t), df)),
+	}, nil
+}
+
+// Returns the sorted Walsh averages of the data, the means of all its
+// pairs of sample points, including each sample point with itself.
+func walshAverages(data []float64) []float64 {
+	n := len(data)
+	averages := make([]float64, 0, n*(n+1)/2)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			averages = append(averages, (data[i]+data[j])/2.0)
+		}
+	}
+	sort.Float64s(averages)
+	return averages
+}
+
+// HodgesLehmann computes the Hodges–Lehmann estimator of the center of
+// a symmetric population, the median of the Walsh averages of the
+// sample: the means of all its pairs of sample points, including each
+// sample point with itself. It is almost as efficient as the mean for
+// Normal populations and robust to up to 29% of outliers.
+//
+// It takes time and memory proportional to the square of the sample
+// size.
+//
+// If the sample size is less than 1, it returns ErrSampleTooSmall.
+func HodgesLehmann(data []float64) (float64, error) {
+	if err := checkSampleSize("HodgesLehmann", len(data), 1); err != nil {
+		return 0.0, err
+	}
+	return median(walshAverages(data)), nil
+}
+
+// HodgesLehmannConfidenceIntervals calculates the confidence intervals
+// of the center of a symmetric population for the given confidence
+// level, the ones found by inverting the Wilcoxon signed-rank test. The
+// coverage is at least the confidence level, unless the sample is too
+// small to reach it, then the intervals span all the Walsh averages. It
+// uses the exact distribution of the statistic for samples of up to 50
+// sample points and its Normal approximation for bigger ones.
+//
+// It takes time and memory proportional to the square of the sample
+// size.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func HodgesLehmannConfidenceIntervals(data []float64, confidence float64) ([2]float64, error) {
+	if err := checkSampleSize("HodgesLehmannConfidenceIntervals", len(data), 2); err != nil {
+		return [2]float64{}, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return [2]float64{}, err
+	}
+
+	averages := walshAverages(data)
+	k := signedRankCriticalValue(len(data), (1.0-confidence)/2.0)
+	if k < 1 {
+		k = 1
+	}
+	return [2]float64{averages[k-1], averages[len(averages)-k]}, nil
+}
+
+// Returns the biggest k such that the probability of the Wilcoxon
+// signed-rank statistic of a sample of size n being less than k is at
+// most p.
+func signedRankCriticalValue(n int, p float64) int {
+	m := n * (n + 1) / 2
+	if n > 50 {
+		mean := float64(m) / 2.0
+		sd := math.Sqrt(float64(n*(n+1)*(2*n+1)) / 24.0)
+		return int(math.Floor(mean + 0.5 + normalQuantile(p)*sd))
+	}
+
+	// probabilities of each value of the statistic, adding one rank at
+	// a time
+	probs := make([]float64, m+1)
+	probs[0] = 1.0
+	for r := 1; r <= n; r++ {
+		for s := m; s >= 0; s-- {
+			probs[s] /= 2.0
+			if s >= r {
+				probs[s] += probs[s-r] / 2.0
+			}
+		}
+	}
+
+	k := 0
+	cumulative := 0.0
+	for k <= m && cumulative+probs[k] <= p {
+		cumulative += probs[k]
+		k++
+	}
+	return k
+}
+
+// Tuning constants of the M-estimators of location that make them 95%
+// as efficient as the mean for Normal populations.
+const (
+	DefaultHuberTuning    = 1.345
+	DefaultBiweightTuning = 4.685
+)
+
+// LocationEstimate is an estimate of the location of a population,
+// along with its standard error and confidence intervals.
+type LocationEstimate struct {
+	Estimate      float64
+	StandardError float64
+	Interval      [2]float64
+}
+
+// The ψ function of an M-estimator, its derivative and the weight
+// ψ(r)/r of its iteratively reweighted least squares.
+type mFunction interface {
+	psi(r float64) float64
+	psiPrime(r float64) float64
+	weight(r float64) float64
+}
+
+type huber float64
+
+func (k huber) psi(r float64) float64 {
+	return math.Max(-float64(k), math.Min(float64(k), r))
+}
+
+func (k huber) psiPrime(r float64) float64 {
+	if math.Abs(r) <= float64(k) {
+		return 1.0
+	}
+	return 0.0
+}
+
+func (k huber) weight(r float64) float64 {
+	if math.Abs(r) <= float64(k) {
+		return 1.0
+	}
+	return float64(k) / math.Abs(r)
+}
+
+type biweight float64
+
+func (c biweight) psi(r float64) float64 {
+	return r * c.weight(r)
+}
+
+func (c biweight) psiPrime(r float64) float64 {
+	u := r / float64(c)
+	if math.Abs(u) >= 1.0 {
+		return 0.0
+	}
+	u2 := u * u
+	return (1.0 - u2) * (1.0 - 5.0*u2)
+}
+
+func (c biweight) weight(r float64) float64 {
+	u := r / float64(c)
+	if math.Abs(u) >= 1.0 {
+		return 0.0
+	}
+	return (1.0 - u*u) * (1.0 - u*u)
+}
+
+// Computes an M-estimate of location with a fixed scale, the median
+// absolute deviation from the median normalized to be consistent with
+// the standard deviation of Normal populations, starting from the
+// median; fn is the function reported in the sample size errors.
+func mEstimate(
+	fn string,
+	data []float64,
+	f mFunction,
+	tuning float64,
+	confidence float64,
+) (LocationEstimate, error) {
+	if !(tuning > 0.0) || math.IsInf(tuning, 1) {
+		return LocationEstimate{}, ErrInvalidTuning
+	}
+	if err := checkSampleSize(fn, len(data), 2); err != nil {
+		return LocationEstimate{}, err
+	}
+	if err := checkConfidence(confidence); err != nil {
+		return LocationEstimate{}, err
+	}
+
+	mu := median(data)
+	scale := medianAbsoluteDeviation(data, mu) / normalMAD
+	if scale == 0.0 {
+		return LocationEstimate{}, ErrZeroVariance
+	}
+
+	for i := 0; i < 100; i++ {
+		var sumW, sumWX float64
+		for _, x := range data {
+			w := f.weight((x - mu) / scale)
+			sumW += w
+			sumWX += w * x
+		}
+		next := sumWX / sumW
+		converged := math.Abs(next-mu) <= 1e-10*scale
+		mu = next
+		if converged {
+			break
+		}
+	}
+
+	var sumPsi2, sumPsiPrime float64
+	for _, x := range data {
+		r := (x - mu) / scale
+		psi := f.psi(r)
+		sumPsi2 += psi * psi
+		sumPsiPrime += f.psiPrime(r)
+	}
+	if !(sumPsiPrime > 0.0) {
+		return LocationEstimate{}, ErrZeroVariance
+	}
+	se := scale * math.Sqrt(sumPsi2) / sumPsiPrime
+
+	tinv, err := studentTwoSidedCriticalValue(int64(len(data)-1), confidence)
+	if err != nil {
+		return LocationEstimate{}, err
+	}
+	margin := tinv * se
+
+	return LocationEstimate{
+		Estimate:      mu,
+		StandardError: se,
+		Interval:      [2]float64{mu - margin, mu + margin},
+	}, nil
+}
+
+// HuberLocation computes Huber's M-estimator of the location of a
+// symmetric population: the mean of the sample with the sample points
+// farther than tuning times the scale from it moved to that distance,
+// where the scale is the median absolute deviation from the median
+// normalized to be consistent with the standard deviation of Normal
+// populations. DefaultHuberTuning is the usual tuning constant; smaller
+// ones are more robust and less efficient.
+//
+// Its standard error is the asymptotic one, and its confidence
+// intervals for the given confidence level use the Student-t
+// distribution with N-1 degrees of freedom.
+//
+// If the tuning constant is not positive, it returns ErrInvalidTuning.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the median absolute deviation is zero, it returns ErrZeroVariance.
+func HuberLocation(data []float64, tuning, confidence float64) (LocationEstimate, error) {
+	return mEstimate("HuberLocation", data, huber(tuning), tuning, confidence)
+}
+
+// BiweightLocation computes Tukey's biweight M-estimator of the
+// location of a symmetric population: the weighted mean of the sample
+// with weights (1-u²)², where u is the distance from the estimate in
+// units of tuning times the scale, so the sample points farther than
+// that are ignored. The scale is the same as in HuberLocation.
+// DefaultBiweightTuning is the usual tuning constant.
+//
+// Its standard error and confidence intervals are like the ones of
+// HuberLocation.
+//
+// If the tuning constant is not positive, it returns ErrInvalidTuning.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+//
+// If the median absolute deviation is zero, or the sample is so spread
+// that the standard error is not defined, it returns ErrZeroVariance.
+func BiweightLocation(data []float64, tuning, confidence float64) (LocationEstimate, error) {
+	return mEstimate("BiweightLocation", data, biweight(tuning), tuning, confidence)
+}
@@ -0,0 +1,199 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+// a sample with an outlier, like a pause in a series of timings
+var withOutlier = []float64{
+	12.1, 9.8, 10.4, 11.0, 10.2, 9.5, 10.9, 35.0, 10.1, 10.6, 9.9, 10.3,
+}
+
+func TestTrimmedMean(t *testing.T) {
+	t.Parallel()
+	mean, err := TrimmedMean(withOutlier, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(mean, 10.425, tolerance) {
+		t.Errorf("wrong trimmed mean: want 10.425, got %f", mean)
+	}
+	se, err := TrimmedStandardError(withOutlier, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(se, 0.2174, tolerance) {
+		t.Errorf("wrong standard error: want 0.2174, got %f", se)
+	}
+	interval, err := TrimmedMeanConfidenceIntervals(withOutlier, 0.2, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [2]float64{9.9108, 10.9392}; !pairEquals(interval, want, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want, interval)
+	}
+
+	// without trimming, they are the mean and its intervals
+	mean, _ = TrimmedMean(withOutlier, 0.0)
+	wantMean, _ := Mean(withOutlier)
+	interval, _ = TrimmedMeanConfidenceIntervals(withOutlier, 0.0, 0.95)
+	wantInterval, _ := MeanConfidenceIntervals(withOutlier, 0.95)
+	if !equals(mean, wantMean, 1e-9) || !pairEquals(interval, wantInterval, 1e-9) {
+		t.Errorf("want %f and %f, got %f and %f",
+			wantMean, wantInterval, mean, interval)
+	}
+}
+
+func TestWinsorizedMean(t *testing.T) {
+	t.Parallel()
+	mean, err := WinsorizedMean(withOutlier, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(mean, 10.4333, tolerance) {
+		t.Errorf("wrong winsorized mean: want 10.4333, got %f", mean)
+	}
+	interval, err := WinsorizedMeanConfidenceIntervals(withOutlier, 0.2, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [2]float64{9.9192, 10.9475}; !pairEquals(interval, want, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want, interval)
+	}
+}
+
+func TestYuen(t *testing.T) {
+	t.Parallel()
+	b := []float64{9.1, 9.6, 8.8, 9.9, 9.4, 30.0, 9.0, 9.7, 9.2, 9.5}
+	result, err := Yuen(withOutlier, b, 0.2, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(result.Difference, 1.0083, tolerance) ||
+		!pairEquals(result.Interval, [2]float64{0.4416, 1.5751}, tolerance) ||
+		!equals(result.Statistic, 3.9045, tolerance) ||
+		!equals(result.DF, 11.2689, tolerance) ||
+		!equals(result.PValue, 0.00235, 1e-5) {
+		t.Errorf("wrong result: %+v", result)
+	}
+
+	if _, err := Yuen(withOutlier, []float64{1.0, 1.0, 1.0, 1.0}, 0.2, 0.95); err != nil {
+		t.Errorf("unexpected error with one constant sample: %v", err)
+	}
+	constant := []float64{1.0, 1.0, 1.0, 1.0, 5.0}
+	if _, err := Yuen(constant, constant, 0.2, 0.95); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("wrong error with constant samples: %v", err)
+	}
+}
+
+func TestHodgesLehmann(t *testing.T) {
+	t.Parallel()
+	estimate, err := HodgesLehmann(withOutlier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(estimate, 10.45, tolerance) {
+		t.Errorf("wrong estimate: want 10.45, got %f", estimate)
+	}
+	interval, err := HodgesLehmannConfidenceIntervals(withOutlier, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [2]float64{10.05, 11.55}; !pairEquals(interval, want, tolerance) {
+		t.Errorf("wrong interval: want %f, got %f", want, interval)
+	}
+}
+
+func TestSignedRankCriticalValue(t *testing.T) {
+	t.Parallel()
+	// from the tables of the two-sided Wilcoxon signed-rank test: the
+	// critical values plus one
+	for _, test := range []struct {
+		n    int
+		p    float64
+		want int
+	}{
+		{5, 0.025, 0},
+		{6, 0.025, 1},
+		{10, 0.025, 9},
+		{10, 0.005, 4},
+		{20, 0.025, 53},
+		{30, 0.025, 138},
+		{50, 0.025, 435},
+	} {
+		if got := signedRankCriticalValue(test.n, test.p); got != test.want {
+			t.Errorf("n=%d, p=%f: want %d, got %d", test.n, test.p, test.want, got)
+		}
+	}
+
+	// the Normal approximation continues the exact values
+	exact := signedRankCriticalValue(50, 0.025)
+	approximated := signedRankCriticalValue(51, 0.025)
+	if approximated < exact || approximated > exact+30 {
+		t.Errorf("approximation far from the exact value: %d, %d", exact, approximated)
+	}
+}
+
+func TestMEstimators(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		f    func([]float64, float64, float64) (LocationEstimate, error)
+		tune float64
+		want LocationEstimate
+	}{
+		{"Huber", HuberLocation, DefaultHuberTuning, LocationEstimate{
+			Estimate:      10.4694,
+			StandardError: 0.2104,
+			Interval:      [2]float64{10.0063, 10.9325},
+		}},
+		{"biweight", BiweightLocation, DefaultBiweightTuning, LocationEstimate{
+			Estimate:      10.3777,
+			StandardError: 0.1908,
+			Interval:      [2]float64{9.9578, 10.7976},
+		}},
+	} {
+		got, err := test.f(withOutlier, test.tune, 0.95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equals(got.Estimate, test.want.Estimate, tolerance) ||
+			!equals(got.StandardError, test.want.StandardError, tolerance) ||
+			!pairEquals(got.Interval, test.want.Interval, tolerance) {
+			t.Errorf("%s: want %+v, got %+v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestRobustErrors(t *testing.T) {
+	t.Parallel()
+	for _, trim := range []float64{-0.1, 0.5, 1.0} {
+		if _, err := TrimmedMean(withOutlier, trim); !errors.Is(err, ErrInvalidTrim) {
+			t.Errorf("wrong error for trim %f: %v", trim, err)
+		}
+	}
+	// trimming 2 of 5 sample points from each end leaves 1
+	data := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+	if _, err := TrimmedMean(data, 0.4); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := TrimmedMeanConfidenceIntervals(data, 0.4, 0.95); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with 1 sample point left: %v", err)
+	}
+	if _, err := WinsorizedMean(nil, 0.1); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with no sample points: %v", err)
+	}
+	if _, err := HodgesLehmann(nil); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error with no sample points: %v", err)
+	}
+	if _, err := HodgesLehmannConfidenceIntervals(data, 1.0); !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("wrong error with confidence 1: %v", err)
+	}
+	if _, err := HuberLocation(data, 0.0, 0.95); !errors.Is(err, ErrInvalidTuning) {
+		t.Errorf("wrong error with tuning 0: %v", err)
+	}
+	if _, err := BiweightLocation([]float64{1.0, 1.0, 1.0, 9.0}, DefaultBiweightTuning, 0.95); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("wrong error with zero MAD: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ package sample // import "github.com/alcortesm/sample"
 import (
 	"errors"
 	"math"
+	"sort"
 )
 
 // ErrSampleTooSmall is returned when the provided data sample set is too small
@@ -15,17 +16,116 @@ import (
 //
 // ErrInvalidConfidenceLevel is returned when the confidence level
 // passed to MeanConfidenceIntervals is not in the valid range.
+//
+// ErrUnsupportedConfidence is returned when a confidence level is valid
+// but bigger than 0.999, the highest level in the table of the Student-t
+// distribution used by MeanConfidenceIntervals and the other functions
+// computing confidence intervals from it.
+//
+// ErrInvalidProbability is returned when a probability argument, like
+// the one passed to ECDF.Quantile, is not in the [0, 1] range.
+//
+// ErrTooFewGroups is returned when a test comparing several groups of
+// sample points is given less than 2 groups.
+//
+// ErrZeroVariance is returned when a computation needs the sample
+// points to have some spread and they are all equal.
+//
+// ErrUnknownAdjustment is returned when a multiple comparison
+// adjustment method is not known or not supported by a computation.
+//
+// ErrUnknownDesign is returned when an experiment design is not known.
+//
+// ErrInvalidEffect is returned when an effect size, a margin of error
+// or a standard deviation passed to a sample size calculation makes it
+// impossible.
+//
+// ErrSampleSizeTooLarge is returned when a sample size calculation needs
+// more than math.MaxInt32 sample points per group.
+//
+// ErrUnknownWeighting is returned when a kind of weights is not known.
+//
+// ErrInvalidWeights is returned when the weights of a sample are
+// negative, not finite or not one per sample point.
+//
+// ErrInvalidHistogram is returned when the bounds of the buckets of a
+// histogram are not finite and strictly increasing, or its counts are
+// negative, not finite or not one per bucket.
+//
+// ErrInvalidSketch is returned when the accuracy parameter of a sketch
+// is out of range, or when decoding a malformed encoded sketch.
+//
+// ErrInvalidRecorder is returned when the range or the precision of a
+// recorder are not valid, or when decoding a malformed encoded
+// recorder.
+//
+// ErrValueOutOfRange is returned when recording a value outside of the
+// range of a recorder.
+//
+// ErrInvalidHalfLife is returned when the half-life of an exponentially
+// weighted statistic is not positive.
+//
+// ErrInvalidWindow is returned when the size or the duration of a
+// sliding window is not positive.
+//
+// ErrInvalidReservoir is returned when the size of a reservoir is not
+// positive, or when merging reservoirs of different sizes.
+//
+// ErrUnknownShapeDefinition is returned when a definition of the
+// skewness and kurtosis is not known.
+//
+// ErrInvalidTrim is returned when the proportion of sample points to
+// trim or winsorize from each end of a sample is not in the [0, 0.5[
+// range.
+//
+// ErrInvalidTuning is returned when the tuning constant of an
+// M-estimator, or the threshold or maximum number of outliers of an
+// outlier detector, is not positive.
+//
+// ErrInvalidOutlier is returned when an outlier to remove has an index
+// outside of the sample.
+//
+// ErrInvalidTarget is returned when the relative half-width Measure
+// tries to reach is not positive.
+//
+// The errors returned by this package for samples too small and invalid
+// confidence levels are a *SampleSizeError and a *ConfidenceError, with
+// the details of the failure; they match ErrSampleTooSmall and
+// ErrInvalidConfidence with errors.Is, which is how they should be
+// checked. Other errors may wrap these sentinels with more details.
 var (
-	ErrSampleTooSmall    = errors.New("too few sample points")
-	ErrInvalidConfidence = errors.New("invalid confidence level, 0 < confidence < 1)")
+	ErrSampleTooSmall         = errors.New("too few sample points")
+	ErrInvalidConfidence      = errors.New("invalid confidence level, 0 < confidence < 1)")
+	ErrUnsupportedConfidence  = errors.New("unsupported confidence level")
+	ErrInvalidProbability     = errors.New("invalid probability, 0 <= p <= 1")
+	ErrTooFewGroups           = errors.New("too few groups")
+	ErrZeroVariance           = errors.New("zero variance")
+	ErrUnknownAdjustment      = errors.New("unknown adjustment method")
+	ErrUnknownDesign          = errors.New("unknown experiment design")
+	ErrInvalidEffect          = errors.New("invalid effect size")
+	ErrSampleSizeTooLarge     = errors.New("sample size too large")
+	ErrUnknownWeighting       = errors.New("unknown kind of weights")
+	ErrInvalidWeights         = errors.New("invalid weights")
+	ErrInvalidHistogram       = errors.New("invalid histogram")
+	ErrInvalidSketch          = errors.New("invalid sketch")
+	ErrInvalidRecorder        = errors.New("invalid recorder")
+	ErrValueOutOfRange        = errors.New("value out of range")
+	ErrInvalidHalfLife        = errors.New("invalid half-life")
+	ErrInvalidWindow          = errors.New("invalid window")
+	ErrInvalidReservoir       = errors.New("invalid reservoir")
+	ErrUnknownShapeDefinition = errors.New("unknown shape definition")
+	ErrInvalidTrim            = errors.New("invalid trimming proportion, 0 <= trim < 0.5")
+	ErrInvalidTuning          = errors.New("invalid tuning constant")
+	ErrInvalidOutlier         = errors.New("invalid outlier index")
+	ErrInvalidTarget          = errors.New("invalid relative half-width target")
 )
 
 // Mean computes the sample mean of a population sample.
 //
 // If the sample size is less than 1, it returns ErrSampleTooSmall
 func Mean(data []float64) (float64, error) {
-	if len(data) < 1 {
-		return 0.0, ErrSampleTooSmall
+	if err := checkSampleSize("Mean", len(data), 1); err != nil {
+		return 0.0, err
 	}
 	return sum(data) / float64(len(data)), nil
 }
@@ -38,6 +138,19 @@ func sum(s []float64) float64 {
 	return sum
 }
 
+// Returns the median of a non-empty slice, without modifying it.
+func median(s []float64) float64 {
+	sorted := make([]float64, len(s))
+	copy(sorted, s)
+	sort.Float64s(sorted)
+
+	m := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[m]
+	}
+	return (sorted[m-1] + sorted[m]) / 2.0
+}
+
 // StandardDeviation computes the sample-based unbiased estimation of the
 // standard deviation of a population.
 //
@@ -48,8 +161,8 @@ func sum(s []float64) float64 {
 //
 // If the sample size is less than 2, it returns ErrSampleTooSmall
 func StandardDeviation(data []float64) (float64, error) {
-	if len(data) < 2 {
-		return 0.0, ErrSampleTooSmall
+	if err := checkSampleSize("StandardDeviation", len(data), 2); err != nil {
+		return 0.0, err
 	}
 
 	mean, _ := Mean(data)
@@ -70,10 +183,10 @@ func StandardDeviation(data []float64) (float64, error) {
 //
 // If the sample size is less than 2, it returns ErrSampleTooSmall
 func StandardError(data []float64) (float64, error) {
-	sd, err := StandardDeviation(data)
-	if err != nil {
+	if err := checkSampleSize("StandardError", len(data), 2); err != nil {
 		return 0.0, err
 	}
+	sd, _ := StandardDeviation(data)
 	return sd / math.Sqrt(float64(len(data))), nil
 }
 
@@ -85,12 +198,13 @@ func StandardError(data []float64) (float64, error) {
 // If the sample size is less than 2, it returns ErrSampleTooSmall.
 //
 // If the confidence value is not in the ]0, 1[ it returns
-// ErrInvalidConfidence.
+// ErrInvalidConfidence, and if it is bigger than 0.999,
+// ErrUnsupportedConfidence.
 func MeanConfidenceIntervals(data []float64, confidence float64) ([2]float64, error) {
-	se, err := StandardError(data)
-	if err != nil {
+	if err := checkSampleSize("MeanConfidenceIntervals", len(data), 2); err != nil {
 		return [2]float64{}, err
 	}
+	se, _ := StandardError(data)
 
 	dimension := int64(len(data) - 1)
 	tinv, err := studentTwoSidedCriticalValue(dimension, confidence)
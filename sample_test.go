@@ -1,6 +1,7 @@
 package sample
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"testing"
@@ -95,7 +96,7 @@ func TestMeanError(t *testing.T) {
 			if err == nil {
 				t.Fatalf("unexpected success")
 			}
-			if err != test.want {
+			if !errors.Is(err, test.want) {
 				t.Errorf("want %q, got %q", test.want, err)
 			}
 		})
@@ -152,7 +153,7 @@ func TestStandardDeviationError(t *testing.T) {
 			if err == nil {
 				t.Fatal("unexpected success")
 			}
-			if err != test.want {
+			if !errors.Is(err, test.want) {
 				t.Errorf("want %f, got %f", test.want, err)
 			}
 		})
@@ -218,7 +219,7 @@ func TestStandardErrorError(t *testing.T) {
 			if err == nil {
 				t.Errorf("unexpected success")
 			}
-			if err != test.want {
+			if !errors.Is(err, test.want) {
 				t.Errorf("want %q, got %q", test.want, err)
 			}
 		})
@@ -334,9 +335,32 @@ func TestMeanConfidenceIntervalsErrors(t *testing.T) {
 			if err == nil {
 				t.Fatal("unexpected success")
 			}
-			if err != test.want {
+			if !errors.Is(err, test.want) {
 				t.Errorf("want %q, got %q", test.want, err)
 			}
 		})
 	}
 }
+
+func TestMedian(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		input []float64
+		want  float64
+	}{
+		{input: []float64{1}, want: 1},
+		{input: []float64{2, 1}, want: 1.5},
+		{input: []float64{3, 1, 2}, want: 2},
+		{input: []float64{4, 1, 3, 2}, want: 2.5},
+	} {
+		input := append([]float64(nil), test.input...)
+		if got := median(input); !equals(got, test.want, tolerance) {
+			t.Errorf("%v: want %f, got %f", test.input, test.want, got)
+		}
+		for i := range input {
+			if input[i] != test.input[i] {
+				t.Errorf("%v: input modified", test.input)
+			}
+		}
+	}
+}
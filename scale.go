@@ -0,0 +1,209 @@
+package sample
+
+import (
+	"math"
+	"sort"
+)
+
+// Φ⁻¹(3/4), the median absolute deviation of the standard Normal
+// distribution.
+const normalMAD = 0.6744897501960817
+
+// MedianAbsoluteDeviation computes the median of the absolute deviations
+// of the sample points from their median, which is robust to up to 50%
+// of outliers, but only 37% as efficient as StandardDeviation for Normal
+// populations. If consistent is true, it is multiplied by 1/Φ⁻¹(3/4),
+// about 1.4826, to estimate the standard deviation of Normal
+// populations, so it can be compared with StandardDeviation.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+func MedianAbsoluteDeviation(data []float64, consistent bool) (float64, error) {
+	if err := checkSampleSize("MedianAbsoluteDeviation", len(data), 2); err != nil {
+		return 0.0, err
+	}
+	mad := medianAbsoluteDeviation(data, median(data))
+	if consistent {
+		mad /= normalMAD
+	}
+	return mad, nil
+}
+
+func medianAbsoluteDeviation(data []float64, center float64) float64 {
+	deviations := make([]float64, len(data))
+	for i, x := range data {
+		deviations[i] = math.Abs(x - center)
+	}
+	return median(deviations)
+}
+
+// Returns the p quantile of the sorted data, interpolating linearly
+// between the sample points, like the default definition of R and
+// NumPy.
+func interpolatedQuantile(sorted []float64, p float64) float64 {
+	h := p * float64(len(sorted)-1)
+	i := int(math.Floor(h))
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (h-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// InterquartileRange computes the difference between the third and the
+// first quartiles of the sample, interpolating linearly between sample
+// points, which is robust to up to 25% of outliers. If consistent is
+// true, it is divided by 2Φ⁻¹(3/4), about 1.349, to estimate the
+// standard deviation of Normal populations.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+func InterquartileRange(data []float64, consistent bool) (float64, error) {
+	if err := checkSampleSize("InterquartileRange", len(data), 2); err != nil {
+		return 0.0, err
+	}
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+
+	iqr := interpolatedQuantile(sorted, 0.75) - interpolatedQuantile(sorted, 0.25)
+	if consistent {
+		iqr /= 2.0 * normalMAD
+	}
+	return iqr, nil
+}
+
+// Returns the k-th smallest, from 1, of the values, reordering them.
+func kthSmallest(values []float64, k int) float64 {
+	sort.Float64s(values)
+	return values[k-1]
+}
+
+// Small sample correction factors of Qn and Sn, from Croux and
+// Rousseeuw (1992), for sample sizes from 2 to 9.
+var (
+	qnCorrections = []float64{0.399, 0.994, 0.512, 0.844, 0.611, 0.857, 0.669, 0.872}
+	snCorrections = []float64{0.743, 1.851, 0.954, 1.351, 0.993, 1.198, 1.005, 1.131}
+)
+
+// Qn computes the Qn estimator of scale of Rousseeuw and Croux (1993),
+// the first quartile of the distances between all the pairs of sample
+// points; more precisely, its k-th smallest, where k is h(h-1)/2 and h
+// is N/2+1. It is robust to up to 50% of outliers and, unlike
+// MedianAbsoluteDeviation, it does not assume a symmetric population and
+// is 82% as efficient as StandardDeviation for Normal populations. If
+// consistent is true, it is multiplied by 1/(√2 Φ⁻¹(5/8)), about 2.2191,
+// and a small sample correction factor, to estimate the standard
+// deviation of Normal populations.
+//
+// It takes time and memory proportional to the square of the sample
+// size.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+func Qn(data []float64, consistent bool) (float64, error) {
+	if err := checkSampleSize("Qn", len(data), 2); err != nil {
+		return 0.0, err
+	}
+	n := len(data)
+	distances := make([]float64, 0, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			distances = append(distances, math.Abs(data[i]-data[j]))
+		}
+	}
+	h := n/2 + 1
+	qn := kthSmallest(distances, h*(h-1)/2)
+	if !consistent {
+		return qn, nil
+	}
+
+	qn *= 1.0 / (math.Sqrt2 * normalQuantile(5.0/8.0))
+	switch {
+	case n <= 9:
+		qn *= qnCorrections[n-2]
+	case n%2 == 1:
+		qn *= float64(n) / (float64(n) + 1.4)
+	default:
+		qn *= float64(n) / (float64(n) + 3.8)
+	}
+	return qn, nil
+}
+
+// Sn computes the Sn estimator of scale of Rousseeuw and Croux (1993),
+// the median over the sample points of the median of their distances to
+// the sample points; more precisely, the low median of the high
+// medians. It is robust to up to 50% of outliers and, unlike
+// MedianAbsoluteDeviation, it does not assume a symmetric population and
+// is 58% as efficient as StandardDeviation for Normal populations. If
+// consistent is true, it is multiplied by 1.1926 and a small sample
+// correction factor, to estimate the standard deviation of Normal
+// populations.
+//
+// It takes time proportional to the square of the sample size.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+func Sn(data []float64, consistent bool) (float64, error) {
+	if err := checkSampleSize("Sn", len(data), 2); err != nil {
+		return 0.0, err
+	}
+	n := len(data)
+	distances := make([]float64, n)
+	medians := make([]float64, n)
+	for i, x := range data {
+		for j, y := range data {
+			distances[j] = math.Abs(x - y)
+		}
+		medians[i] = kthSmallest(distances, n/2+1)
+	}
+	sn := kthSmallest(medians, (n+1)/2)
+	if !consistent {
+		return sn, nil
+	}
+
+	sn *= 1.1926
+	switch {
+	case n <= 9:
+		sn *= snCorrections[n-2]
+	case n%2 == 1:
+		sn *= float64(n) / (float64(n) - 0.9)
+	}
+	return sn, nil
+}
+
+// BiweightMidvariance computes the biweight midvariance of the sample,
+// a robust estimator of the variance of the population that gives
+// decreasing weights to the sample points farther from the median, up
+// to 9 median absolute deviations, and ignores the ones beyond. It is
+// robust to up to 50% of outliers and, for Normal populations, its
+// square root is 87% as efficient as StandardDeviation. If consistent
+// is true, it is divided by 1.0184 to estimate the variance of Normal
+// populations, so its square root can be compared with
+// StandardDeviation.
+//
+// If the sample size is less than 2, it returns ErrSampleTooSmall.
+//
+// If the median absolute deviation is zero, it returns ErrZeroVariance.
+func BiweightMidvariance(data []float64, consistent bool) (float64, error) {
+	if err := checkSampleSize("BiweightMidvariance", len(data), 2); err != nil {
+		return 0.0, err
+	}
+	m := median(data)
+	mad := medianAbsoluteDeviation(data, m)
+	if mad == 0.0 {
+		return 0.0, ErrZeroVariance
+	}
+
+	var numerator, denominator float64
+	for _, x := range data {
+		u := (x - m) / (9.0 * mad)
+		if math.Abs(u) >= 1.0 {
+			continue
+		}
+		u2 := u * u
+		w := (1.0 - u2) * (1.0 - u2)
+		numerator += (x - m) * (x - m) * w * w
+		denominator += (1.0 - u2) * (1.0 - 5.0*u2)
+	}
+	variance := float64(len(data)) * numerator / (denominator * denominator)
+	if consistent {
+		variance /= 1.0184415719960354
+	}
+	return variance, nil
+}
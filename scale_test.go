@@ -0,0 +1,95 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRobustScale(t *testing.T) {
+	t.Parallel()
+	type estimator func([]float64, bool) (float64, error)
+	for _, test := range []struct {
+		name string
+		f    estimator
+		n    int        // sample points of withOutlier used
+		want [2]float64 // raw and consistent
+	}{
+		{"MAD", MedianAbsoluteDeviation, 12, [2]float64{0.5, 0.7413}},
+		{"MAD", MedianAbsoluteDeviation, 7, [2]float64{0.6, 0.8896}},
+		{"IQR", InterquartileRange, 12, [2]float64{0.875, 0.6486}},
+		{"IQR", InterquartileRange, 11, [2]float64{0.95, 0.7042}},
+		{"Qn", Qn, 12, [2]float64{0.5, 0.8427}},
+		{"Qn", Qn, 11, [2]float64{0.4, 0.7874}},
+		{"Qn", Qn, 7, [2]float64{0.6, 1.1411}},
+		{"Sn", Sn, 12, [2]float64{0.5, 0.5963}},
+		{"Sn", Sn, 11, [2]float64{0.6, 0.7793}},
+		{"Sn", Sn, 7, [2]float64{0.7, 1.0001}},
+		{"biweight", BiweightMidvariance, 12, [2]float64{0.4585, 0.4502}},
+		{"biweight", BiweightMidvariance, 7, [2]float64{0.6792, 0.6669}},
+	} {
+		data := withOutlier[:test.n]
+		raw, err := test.f(data, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		consistent, err := test.f(data, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := [2]float64{raw, consistent}
+		if !pairEquals(got, test.want, tolerance) {
+			t.Errorf("%s of %d sample points: want %f, got %f",
+				test.name, test.n, test.want, got)
+		}
+	}
+}
+
+// The consistent estimators estimate the standard deviation of Normal
+// populations.
+func TestRobustScaleConsistency(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewSource(1))
+	data := make([]float64, 2000)
+	for i := range data {
+		data[i] = 5.0 + 3.0*rng.NormFloat64()
+	}
+	sqrtBiweight := func(data []float64, consistent bool) (float64, error) {
+		v, err := BiweightMidvariance(data, consistent)
+		return math.Sqrt(v), err
+	}
+	for name, f := range map[string]func([]float64, bool) (float64, error){
+		"MAD":      MedianAbsoluteDeviation,
+		"IQR":      InterquartileRange,
+		"Qn":       Qn,
+		"Sn":       Sn,
+		"biweight": sqrtBiweight,
+	} {
+		got, err := f(data, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-3.0) > 0.15 {
+			t.Errorf("%s: want about 3, got %f", name, got)
+		}
+	}
+}
+
+func TestRobustScaleErrors(t *testing.T) {
+	t.Parallel()
+	for name, f := range map[string]func([]float64, bool) (float64, error){
+		"MAD":      MedianAbsoluteDeviation,
+		"IQR":      InterquartileRange,
+		"Qn":       Qn,
+		"Sn":       Sn,
+		"biweight": BiweightMidvariance,
+	} {
+		if _, err := f([]float64{1.0}, true); !errors.Is(err, ErrSampleTooSmall) {
+			t.Errorf("%s: wrong error with 1 sample point: %v", name, err)
+		}
+	}
+	if _, err := BiweightMidvariance([]float64{1.0, 1.0, 1.0, 9.0}, true); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("wrong error with zero MAD: %v", err)
+	}
+}
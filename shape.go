@@ -0,0 +1,256 @@
+package sample
+
+import "math"
+
+// ShapeDefinition is a definition of the sample skewness and excess
+// kurtosis; Joanes and Gill (1998) compare them.
+type ShapeDefinition int
+
+// PopulationMoments uses the formulas of the skewness and kurtosis of a
+// population on the sample, g1 = m3/m2^(3/2) and g2 = m4/m2^2 - 3,
+// where mk is the k-th central moment of the sample. They are biased in
+// small samples.
+//
+// FisherPearson uses the adjusted Fisher–Pearson coefficients G1 and G2
+// of SAS, SPSS and Excel, which correct the bias of g1 and g2 for Normal
+// populations.
+//
+// SampleMoments uses b1 = m3/s^3 and b2 = m4/s^4 - 3 of MINITAB and
+// BMDP, where s is the sample-based unbiased estimation of the standard
+// deviation of the population.
+const (
+	PopulationMoments ShapeDefinition = iota
+	FisherPearson
+	SampleMoments
+)
+
+var shapeDefinitionNames = map[ShapeDefinition]string{
+	PopulationMoments: "population moments",
+	FisherPearson:     "adjusted Fisher–Pearson",
+	SampleMoments:     "sample moments",
+}
+
+func (d ShapeDefinition) String() string {
+	if name, ok := shapeDefinitionNames[d]; ok {
+		return name
+	}
+	return "unknown shape definition"
+}
+
+// ShapeStatistic is an estimate of the skewness or the excess kurtosis
+// of a population, its standard error and the z test of the null
+// hypothesis that it is zero, with its two-sided p-value.
+type ShapeStatistic struct {
+	Estimate      float64
+	StandardError float64
+	Z             float64
+	PValue        float64
+}
+
+// Shape describes the shape of the distribution of a sample: its size,
+// the sample mean, the estimation of the standard deviation of the
+// population, and the skewness and excess kurtosis using the Definition.
+type Shape struct {
+	N                 int
+	Mean              float64
+	StandardDeviation float64
+	Definition        ShapeDefinition
+	Skewness          ShapeStatistic
+	Kurtosis          ShapeStatistic
+}
+
+// Accumulates the central moments up to the fourth of a set of sample
+// points in one pass, with the updates of Terriberry (2007).
+type shapeMoments struct {
+	n          int
+	mean       float64
+	m2, m3, m4 float64 // sums of powers of the deviations from the mean
+}
+
+func (m *shapeMoments) add(x float64) {
+	n1 := float64(m.n)
+	m.n++
+	n := float64(m.n)
+	delta := x - m.mean
+	deltaN := delta / n
+	deltaN2 := deltaN * deltaN
+	term := delta * deltaN * n1
+	m.mean += deltaN
+	m.m4 += term*deltaN2*(n*n-3.0*n+3.0) + 6.0*deltaN2*m.m2 - 4.0*deltaN*m.m3
+	m.m3 += term*deltaN*(n-2.0) - 3.0*deltaN*m.m2
+	m.m2 += term
+}
+
+// Returns g1 and g2.
+func (m *shapeMoments) populationShape() (float64, float64) {
+	n := float64(m.n)
+	g1 := math.Sqrt(n) * m.m3 / math.Pow(m.m2, 1.5)
+	g2 := n*m.m4/(m.m2*m.m2) - 3.0
+	return g1, g2
+}
+
+// Computes the one pass moments of data, which must have at least need
+// sample points and a non-zero variance; fn is the function reported in
+// the sample size errors.
+func newShapeMoments(fn string, data []float64, need int) (shapeMoments, error) {
+	if err := checkSampleSize(fn, len(data), need); err != nil {
+		return shapeMoments{}, err
+	}
+	var m shapeMoments
+	for _, x := range data {
+		m.add(x)
+	}
+	if m.m2 == 0.0 {
+		return shapeMoments{}, ErrZeroVariance
+	}
+	return m, nil
+}
+
+// Returns the skewness of the sample, using the definition, along with
+// the factor that converts G1 into it; n must be at least 3.
+func skewness(g1 float64, n float64, definition ShapeDefinition) (float64, float64) {
+	var factor float64
+	switch definition {
+	case PopulationMoments:
+		factor = (n - 2.0) / math.Sqrt(n*(n-1.0))
+	case FisherPearson:
+		factor = 1.0
+	case SampleMoments:
+		factor = (n - 2.0) / math.Sqrt(n*(n-1.0)) * math.Pow((n-1.0)/n, 1.5)
+	}
+	G1 := g1 * math.Sqrt(n*(n-1.0)) / (n - 2.0)
+	return G1 * factor, factor
+}
+
+// Returns the excess kurtosis of the sample, using the definition,
+// along with the slope of its linear relation with G2; n must be at
+// least 4.
+func kurtosis(g2 float64, n float64, definition ShapeDefinition) (float64, float64) {
+	switch definition {
+	case PopulationMoments:
+		return g2, (n - 2.0) * (n - 3.0) / ((n + 1.0) * (n - 1.0))
+	case FisherPearson:
+		return fisherPearsonKurtosis(g2, n), 1.0
+	default:
+		slope := (n - 1.0) * (n - 1.0) / (n * n)
+		return (g2+3.0)*slope - 3.0,
+			slope * (n - 2.0) * (n - 3.0) / ((n + 1.0) * (n - 1.0))
+	}
+}
+
+func fisherPearsonKurtosis(g2 float64, n float64) float64 {
+	return ((n+1.0)*g2 + 6.0) * (n - 1.0) / ((n - 2.0) * (n - 3.0))
+}
+
+// Returns the standard errors of G1 and G2 for samples of size n of a
+// Normal population.
+func shapeStandardErrors(n float64) (float64, float64) {
+	ses := math.Sqrt(6.0 * n * (n - 1.0) / ((n - 2.0) * (n + 1.0) * (n + 3.0)))
+	sek := 2.0 * ses * math.Sqrt((n*n-1.0)/((n-3.0)*(n+5.0)))
+	return ses, sek
+}
+
+// Returns the z test of the null hypothesis that the statistic with
+// the given estimate of G, which is unbiased for Normal populations, is
+// zero.
+func shapeTest(estimate, se, g, seG float64) ShapeStatistic {
+	z := g / seG
+	return ShapeStatistic{
+		Estimate:      estimate,
+		StandardError: se,
+		Z:             z,
+		PValue:        2.0 * normalCDF(-math.Abs(z)),
+	}
+}
+
+// Skewness computes the sample skewness of a population sample using
+// the given definition.
+//
+// If the sample size is less than 3, it returns ErrSampleTooSmall.
+//
+// If all the sample points are equal, it returns ErrZeroVariance.
+//
+// If the definition is not one of the ShapeDefinition constants, it
+// returns ErrUnknownShapeDefinition.
+func Skewness(data []float64, definition ShapeDefinition) (float64, error) {
+	if _, ok := shapeDefinitionNames[definition]; !ok {
+		return 0.0, ErrUnknownShapeDefinition
+	}
+	m, err := newShapeMoments("Skewness", data, 3)
+	if err != nil {
+		return 0.0, err
+	}
+	g1, _ := m.populationShape()
+	estimate, _ := skewness(g1, float64(m.n), definition)
+	return estimate, nil
+}
+
+// Kurtosis computes the sample excess kurtosis of a population sample,
+// the kurtosis minus the 3 of Normal populations, using the given
+// definition.
+//
+// If the sample size is less than 4, it returns ErrSampleTooSmall.
+//
+// If all the sample points are equal, it returns ErrZeroVariance.
+//
+// If the definition is not one of the ShapeDefinition constants, it
+// returns ErrUnknownShapeDefinition.
+func Kurtosis(data []float64, definition ShapeDefinition) (float64, error) {
+	if _, ok := shapeDefinitionNames[definition]; !ok {
+		return 0.0, ErrUnknownShapeDefinition
+	}
+	m, err := newShapeMoments("Kurtosis", data, 4)
+	if err != nil {
+		return 0.0, err
+	}
+	_, g2 := m.populationShape()
+	estimate, _ := kurtosis(g2, float64(m.n), definition)
+	return estimate, nil
+}
+
+// DescribeShape computes, in one pass over the data, the sample mean,
+// standard deviation, skewness and excess kurtosis of a population
+// sample using the given definition, along with the standard errors of
+// the skewness and kurtosis and the z tests of the null hypotheses that
+// they are zero.
+//
+// The standard errors are the exact ones for samples of Normal
+// populations. Whatever the definition, the z tests use G1 and G2,
+// which are unbiased for Normal populations, so their z and p-values do
+// not change with it. They need big samples: the z test of the skewness
+// is accurate from about 150 sample points and the one of the kurtosis
+// from about 1000.
+//
+// If the sample size is less than 4, it returns ErrSampleTooSmall.
+//
+// If all the sample points are equal, it returns ErrZeroVariance.
+//
+// If the definition is not one of the ShapeDefinition constants, it
+// returns ErrUnknownShapeDefinition.
+func DescribeShape(data []float64, definition ShapeDefinition) (Shape, error) {
+	if _, ok := shapeDefinitionNames[definition]; !ok {
+		return Shape{}, ErrUnknownShapeDefinition
+	}
+	m, err := newShapeMoments("DescribeShape", data, 4)
+	if err != nil {
+		return Shape{}, err
+	}
+
+	n := float64(m.n)
+	g1, g2 := m.populationShape()
+	ses, sek := shapeStandardErrors(n)
+
+	skew, skewFactor := skewness(g1, n, definition)
+	skewG, _ := skewness(g1, n, FisherPearson)
+	kurt, kurtFactor := kurtosis(g2, n, definition)
+	kurtG := fisherPearsonKurtosis(g2, n)
+
+	return Shape{
+		N:                 m.n,
+		Mean:              m.mean,
+		StandardDeviation: math.Sqrt(m.m2 / (n - 1.0)),
+		Definition:        definition,
+		Skewness:          shapeTest(skew, ses*skewFactor, skewG, ses),
+		Kurtosis:          shapeTest(kurt, sek*kurtFactor, kurtG, sek),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDescribeShape(t *testing.T) {
+	t.Parallel()
+	data := []float64{2.0, 8.0, 0.0, 4.0, 1.0, 9.0, 9.0, 0.0, 3.5, 12.0}
+	for _, test := range []struct {
+		definition ShapeDefinition
+		skewness   [2]float64 // estimate and standard error
+		kurtosis   [2]float64
+	}{
+		{PopulationMoments, [2]float64{0.3314, 0.5794}, [2]float64{-1.3348, 0.7547}},
+		{FisherPearson, [2]float64{0.3930, 0.6870}, [2]float64{-1.3954, 1.3342}},
+		{SampleMoments, [2]float64{0.2830, 0.4947}, [2]float64{-1.6512, 0.6113}},
+	} {
+		test := test
+		t.Run(test.definition.String(), func(t *testing.T) {
+			t.Parallel()
+			shape, err := DescribeShape(data, test.definition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if shape.N != 10 || shape.Definition != test.definition ||
+				!equals(shape.Mean, 4.85, tolerance) ||
+				!equals(shape.StandardDeviation, 4.3208, tolerance) {
+				t.Errorf("wrong summary: %+v", shape)
+			}
+
+			got := [2]float64{shape.Skewness.Estimate, shape.Skewness.StandardError}
+			if !pairEquals(got, test.skewness, tolerance) {
+				t.Errorf("wrong skewness: want %f, got %f", test.skewness, got)
+			}
+			got = [2]float64{shape.Kurtosis.Estimate, shape.Kurtosis.StandardError}
+			if !pairEquals(got, test.kurtosis, tolerance) {
+				t.Errorf("wrong kurtosis: want %f, got %f", test.kurtosis, got)
+			}
+
+			// the tests do not depend on the definition
+			gotTests := [2]float64{shape.Skewness.Z, shape.Skewness.PValue}
+			if want := [2]float64{0.5721, 0.5673}; !pairEquals(gotTests, want, tolerance) {
+				t.Errorf("wrong skewness test: want %f, got %f", want, gotTests)
+			}
+			gotTests = [2]float64{shape.Kurtosis.Z, shape.Kurtosis.PValue}
+			if want := [2]float64{-1.0458, 0.2956}; !pairEquals(gotTests, want, tolerance) {
+				t.Errorf("wrong kurtosis test: want %f, got %f", want, gotTests)
+			}
+
+			skew, err := Skewness(data, test.definition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kurt, err := Kurtosis(data, test.definition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if skew != shape.Skewness.Estimate || kurt != shape.Kurtosis.Estimate {
+				t.Errorf("want %f and %f, got %f and %f",
+					shape.Skewness.Estimate, shape.Kurtosis.Estimate, skew, kurt)
+			}
+		})
+	}
+}
+
+// The one pass algorithm must not lose precision when the mean is big
+// compared to the standard deviation.
+func TestDescribeShapeStability(t *testing.T) {
+	t.Parallel()
+	data := []float64{2.0, 8.0, 0.0, 4.0, 1.0, 9.0, 9.0, 0.0, 3.5, 12.0}
+	want, _ := DescribeShape(data, FisherPearson)
+	shifted := make([]float64, len(data))
+	for i, x := range data {
+		shifted[i] = x + 1e9
+	}
+	got, err := DescribeShape(shifted, FisherPearson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(got.Skewness.Estimate, want.Skewness.Estimate, 1e-6) ||
+		!equals(got.Kurtosis.Estimate, want.Kurtosis.Estimate, 1e-6) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestShapeErrors(t *testing.T) {
+	t.Parallel()
+	if _, err := Skewness([]float64{1.0, 2.0}, FisherPearson); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error for 2 sample points: %v", err)
+	}
+	if _, err := Kurtosis([]float64{1.0, 2.0, 3.0}, FisherPearson); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error for 3 sample points: %v", err)
+	}
+	if _, err := DescribeShape([]float64{1.0, 2.0, 3.0}, FisherPearson); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("wrong error for 3 sample points: %v", err)
+	}
+	if _, err := DescribeShape([]float64{1.0, 1.0, 1.0, 1.0}, FisherPearson); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("wrong error for equal sample points: %v", err)
+	}
+	data := []float64{1.0, 2.0, 3.0, 5.0}
+	for _, f := range []func([]float64, ShapeDefinition) (float64, error){Skewness, Kurtosis} {
+		if _, err := f(data, ShapeDefinition(-1)); !errors.Is(err, ErrUnknownShapeDefinition) {
+			t.Errorf("wrong error for unknown definition: %v", err)
+		}
+	}
+	if _, err := DescribeShape(data, ShapeDefinition(3)); !errors.Is(err, ErrUnknownShapeDefinition) {
+		t.Errorf("wrong error for unknown definition: %v", err)
+	}
+	if got := ShapeDefinition(3).String(); got != "unknown shape definition" {
+		t.Errorf("wrong name: %q", got)
+	}
+}
@@ -0,0 +1,405 @@
+package sample
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// QuantileSketch estimates the quantiles and the cumulative distribution
+// function of a stream of sample points, using a fixed amount of memory
+// regardless of the number of points added. Sketches of different parts
+// of a stream can be merged, and encoded to be sent or stored.
+//
+// It is a KLL sketch (Karnin, Lang and Liberty, 2016): sample points are
+// kept in levels of compactors, where each point of level h stands for
+// 2^h points of the stream. When a level is full, it is sorted and half
+// of its points, either the odd or the even ones at random, are promoted
+// to the next level, the rest are discarded.
+//
+// Its accuracy is measured by the rank error: the difference between
+// the fraction of the stream that is less than or equal to an estimated
+// quantile and the requested probability. With the accuracy parameter
+// k, the rank error of a quantile is less than 2/k with probability
+// 99%, and the rank error of all the quantiles at once less than 3/k
+// with the same probability; the sketch keeps about 3k sample points.
+// The same bounds apply to the CDF.
+//
+// A QuantileSketch is not safe for concurrent use. The zero value is
+// not usable, use NewQuantileSketch to create one.
+type QuantileSketch struct {
+	k          int
+	n          uint64
+	min        float64
+	max        float64
+	compactors [][]float64
+	size       int    // number of points in all the compactors
+	maxSize    int    // size that triggers a compression
+	rng        uint64 // state of a splitmix64 generator
+}
+
+// MinSketchAccuracy is the minimum accuracy parameter of a
+// QuantileSketch.
+const MinSketchAccuracy = 8
+
+// DefaultSketchAccuracy is an accuracy parameter giving rank errors
+// below 1% with a few kilobytes of memory.
+const DefaultSketchAccuracy = 200
+
+// NewQuantileSketch returns an empty sketch with the given accuracy
+// parameter k, see QuantileSketch for its meaning. The coins deciding
+// which points are promoted are drawn from a source of randomness with
+// a different seed for each sketch, so the errors of sketches of
+// different parts of a stream are independent.
+//
+// If k is less than MinSketchAccuracy, it returns ErrInvalidSketch.
+func NewQuantileSketch(k int) (*QuantileSketch, error) {
+	return newQuantileSketch(k, rand.Uint64())
+}
+
+// NewSeededQuantileSketch is like NewQuantileSketch, but the seed
+// initializes its source of randomness, so sketches with the same seed
+// fed with the same sample points have the same contents. Sketches to
+// be merged should have unrelated seeds.
+func NewSeededQuantileSketch(k int, seed int64) (*QuantileSketch, error) {
+	return newQuantileSketch(k, uint64(seed))
+}
+
+func newQuantileSketch(k int, seed uint64) (*QuantileSketch, error) {
+	if k < MinSketchAccuracy {
+		return nil, ErrInvalidSketch
+	}
+
+	s := &QuantileSketch{
+		k:   k,
+		min: math.Inf(1),
+		max: math.Inf(-1),
+		rng: seed,
+	}
+	s.grow()
+
+	return s, nil
+}
+
+// Adds a level to the sketch, updating the maximum size.
+func (s *QuantileSketch) grow() {
+	s.compactors = append(s.compactors, nil)
+	s.maxSize = 0
+	for h := range s.compactors {
+		s.maxSize += s.capacity(h)
+	}
+}
+
+// Returns the capacity of the level h: k for the top level, decreasing
+// geometrically by a factor of 2/3 for the levels below, and never less
+// than 2.
+func (s *QuantileSketch) capacity(h int) int {
+	depth := len(s.compactors) - h - 1
+	c := int(math.Ceil(float64(s.k) * math.Pow(2.0/3.0, float64(depth))))
+	if c < 2 {
+		return 2
+	}
+	return c
+}
+
+// Returns a random bit, from a splitmix64 generator.
+func (s *QuantileSketch) coin() int {
+	s.rng += 0x9e3779b97f4a7c15
+	z := s.rng
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return int(z >> 63)
+}
+
+// Add adds a sample point to the sketch. NaNs are ignored.
+func (s *QuantileSketch) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+
+	s.n++
+	s.min = math.Min(s.min, x)
+	s.max = math.Max(s.max, x)
+	s.compactors[0] = append(s.compactors[0], x)
+	s.size++
+	if s.size >= s.maxSize {
+		s.compress()
+	}
+}
+
+// Compacts the lowest full levels until the sketch is below its
+// maximum size.
+func (s *QuantileSketch) compress() {
+	for h := 0; h < len(s.compactors); h++ {
+		if len(s.compactors[h]) < s.capacity(h) {
+			continue
+		}
+		if h+1 == len(s.compactors) {
+			s.grow()
+		}
+
+		level := s.compactors[h]
+		sort.Float64s(level)
+		// an odd point out stays in the level
+		keep := len(level) % 2
+		for i := keep + s.coin(); i < len(level); i += 2 {
+			s.compactors[h+1] = append(s.compactors[h+1], level[i])
+		}
+		s.compactors[h] = level[:keep]
+
+		s.size = 0
+		for _, c := range s.compactors {
+			s.size += len(c)
+		}
+		if s.size < s.maxSize {
+			return
+		}
+	}
+}
+
+// Merge adds all the sample points of other to the sketch, as if they
+// had been added to it with Add; other is not modified.
+//
+// The sketches may have different accuracy parameters. The result keeps
+// the k of the sketch, and its memory, but the points of other already
+// carry the errors of its own k, so the rank error of the result is
+// bounded by the one of the smaller k.
+func (s *QuantileSketch) Merge(other *QuantileSketch) {
+	if other.n == 0 {
+		return
+	}
+
+	for len(s.compactors) < len(other.compactors) {
+		s.grow()
+	}
+	for h, c := range other.compactors {
+		s.compactors[h] = append(s.compactors[h], c...)
+		s.size += len(c)
+	}
+	s.n += other.n
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+
+	for s.size >= s.maxSize {
+		s.compress()
+	}
+}
+
+// N returns the number of sample points added to the sketch.
+func (s *QuantileSketch) N() uint64 {
+	return s.n
+}
+
+// Returns the sample points kept in the sketch sorted by value, and
+// their weights.
+func (s *QuantileSketch) sorted() (values []float64, weights []uint64) {
+	type item struct {
+		value  float64
+		weight uint64
+	}
+	items := make([]item, 0, s.size)
+	for h, c := range s.compactors {
+		for _, v := range c {
+			items = append(items, item{value: v, weight: 1 << uint(h)})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].value < items[j].value
+	})
+
+	values = make([]float64, len(items))
+	weights = make([]uint64, len(items))
+	for i, it := range items {
+		values[i] = it.value
+		weights[i] = it.weight
+	}
+	return values, weights
+}
+
+// Quantile estimates the p-quantile of the sample points added, as the
+// smallest kept sample point whose estimated CDF is at least p.
+// Quantile(0) and Quantile(1) are the exact minimum and maximum.
+//
+// If no sample points were added, it returns ErrSampleTooSmall.
+//
+// If p is not in the [0, 1] range, it returns ErrInvalidProbability.
+func (s *QuantileSketch) Quantile(p float64) (float64, error) {
+	if s.n == 0 {
+		return 0.0, &SampleSizeError{Func: "QuantileSketch.Quantile", Got: 0, Need: 1}
+	}
+	if !(p >= 0.0 && p <= 1.0) {
+		return 0.0, ErrInvalidProbability
+	}
+	switch p {
+	case 0.0:
+		return s.min, nil
+	case 1.0:
+		return s.max, nil
+	}
+
+	values, weights := s.sorted()
+	target := p * float64(s.n)
+	cumulative := uint64(0)
+	for i, v := range values {
+		cumulative += weights[i]
+		if float64(cumulative) >= target {
+			return v, nil
+		}
+	}
+	return s.max, nil
+}
+
+// CDF estimates the fraction of the sample points added that are less
+// than or equal to x. It returns 0 if no sample points were added.
+func (s *QuantileSketch) CDF(x float64) float64 {
+	if s.n == 0 || x < s.min {
+		return 0.0
+	}
+	if x >= s.max {
+		return 1.0
+	}
+
+	below := uint64(0)
+	for h, c := range s.compactors {
+		for _, v := range c {
+			if v <= x {
+				below += 1 << uint(h)
+			}
+		}
+	}
+	return float64(below) / float64(s.n)
+}
+
+// Version of the binary encoding of QuantileSketch.
+const sketchEncodingVersion = 1
+
+// MarshalBinary encodes the sketch as a version byte followed by k,
+// the number of sample points, the state of the random generator and
+// the number of levels as unsigned varints, then the minimum and
+// maximum as little-endian float64s, then each level as its length as
+// an unsigned varint followed by its sample points as little-endian
+// float64s.
+func (s *QuantileSketch) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 1+5*binary.MaxVarintLen64+16+8*s.size+
+		len(s.compactors)*binary.MaxVarintLen64)
+
+	b = append(b, sketchEncodingVersion)
+	b = appendUvarint(b, uint64(s.k))
+	b = appendUvarint(b, s.n)
+	b = appendUvarint(b, s.rng)
+	b = appendUvarint(b, uint64(len(s.compactors)))
+	b = appendFloat64(b, s.min)
+	b = appendFloat64(b, s.max)
+	for _, c := range s.compactors {
+		b = appendUvarint(b, uint64(len(c)))
+		for _, v := range c {
+			b = appendFloat64(b, v)
+		}
+	}
+
+	return b, nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendFloat64(b []byte, v float64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	return append(b, buf[:]...)
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary, replacing
+// the contents of s.
+//
+// If the data is malformed, it returns ErrInvalidSketch.
+func (s *QuantileSketch) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.byte() != sketchEncodingVersion {
+		return ErrInvalidSketch
+	}
+
+	k := d.uvarint()
+	n := d.uvarint()
+	rng := d.uvarint()
+	levels := d.uvarint()
+	min := d.float64()
+	max := d.float64()
+	if d.err || k < MinSketchAccuracy || k > math.MaxInt32 || levels < 1 || levels > 64 {
+		return ErrInvalidSketch
+	}
+
+	decoded := &QuantileSketch{
+		k:   int(k),
+		n:   n,
+		min: min,
+		max: max,
+		rng: rng,
+	}
+	total := uint64(0)
+	for h := uint64(0); h < levels; h++ {
+		decoded.grow()
+		length := d.uvarint()
+		if d.err || length > uint64(len(d.data))/8 {
+			return ErrInvalidSketch
+		}
+		c := make([]float64, length)
+		for i := range c {
+			c[i] = d.float64()
+			if math.IsNaN(c[i]) || c[i] < min || c[i] > max {
+				return ErrInvalidSketch
+			}
+		}
+		decoded.compactors[h] = c
+		decoded.size += len(c)
+		total += length << h
+	}
+	if d.err || len(d.data) != 0 || total != n {
+		return ErrInvalidSketch
+	}
+
+	*s = *decoded
+	return nil
+}
+
+// Reads the fields of a binary encoding, remembering if any of them
+// was truncated or malformed.
+type decoder struct {
+	data []byte
+	err  bool
+}
+
+func (d *decoder) byte() byte {
+	if len(d.data) < 1 {
+		d.err = true
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = true
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) float64() float64 {
+	if len(d.data) < 8 {
+		d.err = true
+		return 0.0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+	d.data = d.data[8:]
+	return v
+}
@@ -0,0 +1,246 @@
+package sample
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Returns the largest rank error of the quantiles of the sketch at
+// p = 0.01, 0.02 ... 0.99, compared with the exact sorted sample.
+func maxRankError(t *testing.T, s *QuantileSketch, sorted []float64) float64 {
+	t.Helper()
+	maxError := 0.0
+	for i := 1; i < 100; i++ {
+		p := float64(i) / 100.0
+		q, err := s.Quantile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rank := sort.Search(len(sorted), func(i int) bool {
+			return sorted[i] > q
+		})
+		maxError = math.Max(maxError, math.Abs(float64(rank)/float64(len(sorted))-p))
+	}
+	return maxError
+}
+
+func TestQuantileSketchRankError(t *testing.T) {
+	t.Parallel()
+	const k = 100
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		s, err := NewSeededQuantileSketch(k, rng.Int63())
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := make([]float64, 20000)
+		for i := range data {
+			data[i] = rng.ExpFloat64()
+			s.Add(data[i])
+		}
+		sort.Float64s(data)
+
+		if s.N() != uint64(len(data)) {
+			t.Errorf("wrong N: want %d, got %d", len(data), s.N())
+		}
+		if got := maxRankError(t, s, data); got > 3.0/k {
+			t.Errorf("trial %d: rank error %f bigger than %f", trial, got, 3.0/k)
+		}
+		if s.size > 4*k {
+			t.Errorf("trial %d: too many points kept: %d", trial, s.size)
+		}
+
+		for _, x := range []float64{0.1, 0.5, 1, 2, 4} {
+			want := float64(sort.SearchFloat64s(data, x)) / float64(len(data))
+			if got := s.CDF(x); math.Abs(got-want) > 3.0/k {
+				t.Errorf("trial %d: CDF(%f): want %f, got %f", trial, x, want, got)
+			}
+		}
+	}
+}
+
+func TestQuantileSketchExact(t *testing.T) {
+	t.Parallel()
+	// small samples are kept whole
+	s, err := NewQuantileSketch(DefaultSketchAccuracy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range []float64{9, 2, math.NaN(), 6, 3, 5} {
+		s.Add(x)
+	}
+	ecdf, err := NewECDF([]float64{9, 2, 6, 3, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []float64{0, 0.2, 0.21, 0.5, 0.8, 0.99, 1} {
+		want, _ := ecdf.Quantile(p)
+		got, err := s.Quantile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("p=%v: want %f, got %f", p, want, got)
+		}
+	}
+	for _, x := range []float64{1, 2, 4, 9, 10} {
+		if got, want := s.CDF(x), ecdf.Eval(x); got != want {
+			t.Errorf("CDF(%v): want %f, got %f", x, want, got)
+		}
+	}
+}
+
+func TestQuantileSketchMerge(t *testing.T) {
+	t.Parallel()
+	const k = 100
+	rng := rand.New(rand.NewSource(2))
+	merged, err := NewSeededQuantileSketch(k, rng.Int63())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []float64{}
+	for part := 0; part < 8; part++ {
+		s, err := NewSeededQuantileSketch(k, rng.Int63())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// parts of different sizes and ranges
+		for i := 0; i < 1000*(part+1); i++ {
+			x := rng.NormFloat64() + float64(part)
+			data = append(data, x)
+			s.Add(x)
+		}
+		merged.Merge(s)
+	}
+	sort.Float64s(data)
+
+	if merged.N() != uint64(len(data)) {
+		t.Errorf("wrong N: want %d, got %d", len(data), merged.N())
+	}
+	if got := maxRankError(t, merged, data); got > 3.0/k {
+		t.Errorf("rank error %f bigger than %f", got, 3.0/k)
+	}
+	if min, _ := merged.Quantile(0); min != data[0] {
+		t.Errorf("wrong minimum: want %f, got %f", data[0], min)
+	}
+	if max, _ := merged.Quantile(1); max != data[len(data)-1] {
+		t.Errorf("wrong maximum: want %f, got %f", data[len(data)-1], max)
+	}
+}
+
+func TestQuantileSketchSeed(t *testing.T) {
+	t.Parallel()
+	const k = 20
+	newSketches := map[string]func() (*QuantileSketch, error){
+		"same seed": func() (*QuantileSketch, error) { return NewSeededQuantileSketch(k, 7) },
+		"no seed":   func() (*QuantileSketch, error) { return NewQuantileSketch(k) },
+	}
+	for description, newSketch := range newSketches {
+		a, err := newSketch()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := newSketch()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			a.Add(float64(i))
+			b.Add(float64(i))
+		}
+		encodedA, _ := a.MarshalBinary()
+		encodedB, _ := b.MarshalBinary()
+		if same := bytes.Equal(encodedA, encodedB); same != (description == "same seed") {
+			t.Errorf("%s: sketches fed with the same points are equal: %t", description, same)
+		}
+	}
+}
+
+func TestQuantileSketchBinary(t *testing.T) {
+	t.Parallel()
+	s, err := NewQuantileSketch(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 10000; i++ {
+		s.Add(rng.Float64())
+	}
+
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) > 9*s.size+64 {
+		t.Errorf("encoding too big: %d bytes for %d points", len(b), s.size)
+	}
+
+	decoded := &QuantileSketch{}
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.N() != s.N() {
+		t.Errorf("wrong N: want %d, got %d", s.N(), decoded.N())
+	}
+	for _, p := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		want, _ := s.Quantile(p)
+		got, _ := decoded.Quantile(p)
+		if got != want {
+			t.Errorf("p=%v: want %f, got %f", p, want, got)
+		}
+	}
+
+	// both must keep evolving in the same way
+	for i := 0; i < 10000; i++ {
+		x := rng.Float64()
+		s.Add(x)
+		decoded.Add(x)
+	}
+	for _, p := range []float64{0.1, 0.5, 0.9} {
+		want, _ := s.Quantile(p)
+		got, _ := decoded.Quantile(p)
+		if got != want {
+			t.Errorf("after adding, p=%v: want %f, got %f", p, want, got)
+		}
+	}
+
+	for _, malformed := range [][]byte{
+		nil,
+		{42},
+		b[:len(b)-1],
+		append(append([]byte{}, b...), 0),
+	} {
+		if err := decoded.UnmarshalBinary(malformed); !errors.Is(err, ErrInvalidSketch) {
+			t.Errorf("want %q, got %v", ErrInvalidSketch, err)
+		}
+	}
+}
+
+func TestQuantileSketchErrors(t *testing.T) {
+	t.Parallel()
+	if _, err := NewQuantileSketch(MinSketchAccuracy - 1); !errors.Is(err, ErrInvalidSketch) {
+		t.Errorf("want %q, got %v", ErrInvalidSketch, err)
+	}
+
+	s, err := NewQuantileSketch(MinSketchAccuracy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Quantile(0.5); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	if got := s.CDF(1); got != 0 {
+		t.Errorf("want CDF 0 of an empty sketch, got %f", got)
+	}
+
+	s.Add(1)
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := s.Quantile(p); !errors.Is(err, ErrInvalidProbability) {
+			t.Errorf("p=%v: want %q, got %v", p, ErrInvalidProbability, err)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+/*
+Package table loads columns of numbers from CSV, TSV and similar
+delimited files, so they can be passed to the functions in the sample
+package.
+*/
+package table // import "github.com/alcortesm/sample/table"
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alcortesm/sample"
+)
+
+// Options configure how Read parses its input. The zero value reads
+// comma separated values without a header line, loading every numeric
+// column.
+type Options struct {
+	// Comma is the field delimiter, ',' if zero. Use '\t' for TSV.
+	Comma rune
+	// Header tells if the first line holds the names of the columns.
+	// Without a header, columns are named after their 0-based index:
+	// "0", "1", and so on.
+	Header bool
+	// Names and Indexes select the columns to load, by name or by
+	// 0-based index. If both are empty, every numeric column is loaded.
+	Names   []string
+	Indexes []int
+	// Missing are the tokens that denote a missing value, which are
+	// skipped. Empty fields are always missing. If nil, "NA", "N/A",
+	// "NaN", "null" and "-" are used.
+	Missing []string
+	// Decimal is the decimal separator, '.' if zero. Use ',' for
+	// locales like the Spanish or the German one.
+	Decimal rune
+	// Thousands is the digit grouping separator, which is removed
+	// before parsing numbers. Zero means numbers are not grouped.
+	Thousands rune
+}
+
+var defaultMissing = []string{"NA", "N/A", "NaN", "null", "-"}
+
+var errMissingField = errors.New("missing field")
+
+// Column is a named column of numbers.
+type Column struct {
+	Name   string
+	Values []float64
+}
+
+// RowError is an error parsing a field of the input. Line is the
+// 1-based line number of the record in the input.
+type RowError struct {
+	Line   int
+	Column string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying parsing error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Table is the outcome of Read.
+//
+// Columns are the columns loaded, in the order they appear in the
+// input. Errors are the fields of the selected columns that could not
+// be parsed, they are skipped like missing values. NonNumeric are the
+// names of the columns that were not loaded because they contain
+// something other than numbers, it is only used when no columns are
+// selected. NonNumericErrors are the first parsing error of each of
+// those columns, in the same order, to tell a column of text from a
+// numeric one with a typo; select the column to load it anyway.
+type Table struct {
+	Columns          []Column
+	Errors           []*RowError
+	NonNumeric       []string
+	NonNumericErrors []*RowError
+}
+
+// Column returns the column with the given name, or nil if there is no
+// such column.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// Read loads columns of numbers from r.
+//
+// It only returns an error if the input cannot be read or is not valid
+// delimited data, or if a selected column does not exist; errors
+// parsing individual fields are reported in the Errors of the table
+// instead.
+func Read(r io.Reader, opts Options) (*Table, error) {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	missing := opts.Missing
+	if missing == nil {
+		missing = defaultMissing
+	}
+
+	var names []string
+	var selected []int
+	var columns []*loader
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if columns == nil {
+			if opts.Header {
+				names = record
+			} else {
+				names = make([]string, len(record))
+				for i := range names {
+					names[i] = strconv.Itoa(i)
+				}
+			}
+			if selected, err = selectColumns(names, opts); err != nil {
+				return nil, err
+			}
+			columns = make([]*loader, len(selected))
+			for i, index := range selected {
+				columns[i] = &loader{
+					Column: Column{Name: names[index], Values: []float64{}},
+				}
+			}
+			if opts.Header {
+				continue
+			}
+		}
+
+		for i, index := range selected {
+			c := columns[i]
+			if index >= len(record) {
+				c.errors = append(c.errors, &RowError{
+					Line:   line,
+					Column: c.Name,
+					Err:    errMissingField,
+				})
+				continue
+			}
+
+			field := strings.TrimSpace(record[index])
+			if isMissing(field, missing) {
+				continue
+			}
+			v, err := parseNumber(field, opts)
+			if err != nil {
+				c.errors = append(c.errors, &RowError{
+					Line:   line,
+					Column: c.Name,
+					Err:    err,
+				})
+				continue
+			}
+			c.Values = append(c.Values, v)
+		}
+	}
+
+	// every numeric column is loaded if none is selected
+	auto := len(opts.Names) == 0 && len(opts.Indexes) == 0
+
+	t := &Table{Columns: []Column{}}
+	for _, c := range columns {
+		if auto && len(c.errors) > 0 {
+			t.NonNumeric = append(t.NonNumeric, c.Name)
+			t.NonNumericErrors = append(t.NonNumericErrors, c.errors[0])
+			continue
+		}
+		t.Columns = append(t.Columns, c.Column)
+		t.Errors = append(t.Errors, c.errors...)
+	}
+	sortRowErrors(t.Errors)
+
+	return t, nil
+}
+
+type loader struct {
+	Column
+	errors []*RowError
+}
+
+// Returns the indexes of the selected columns, in the order they appear
+// in the input.
+func selectColumns(names []string, opts Options) ([]int, error) {
+	if len(opts.Names) == 0 && len(opts.Indexes) == 0 {
+		all := make([]int, len(names))
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	isSelected := make([]bool, len(names))
+	for _, index := range opts.Indexes {
+		if index < 0 || index >= len(names) {
+			return nil, fmt.Errorf("column index %d out of range", index)
+		}
+		isSelected[index] = true
+	}
+	for _, name := range opts.Names {
+		found := false
+		for i := range names {
+			if names[i] == name {
+				isSelected[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+	}
+
+	selected := []int{}
+	for i, ok := range isSelected {
+		if ok {
+			selected = append(selected, i)
+		}
+	}
+	return selected, nil
+}
+
+// Sorts the errors by line, keeping the order of the columns for errors
+// in the same line.
+func sortRowErrors(errs []*RowError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
+}
+
+func isMissing(field string, missing []string) bool {
+	if field == "" {
+		return true
+	}
+	for _, token := range missing {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNumber(field string, opts Options) (float64, error) {
+	if opts.Thousands != 0 {
+		field = strings.Replace(field, string(opts.Thousands), "", -1)
+	}
+	if opts.Decimal != 0 && opts.Decimal != '.' {
+		if strings.ContainsRune(field, '.') {
+			return 0, fmt.Errorf("invalid number %q", field)
+		}
+		field = strings.Replace(field, string(opts.Decimal), ".", 1)
+	}
+
+	v, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", field)
+	}
+	return v, nil
+}
+
+// ColumnSummary is the summary of a column, or the error that prevented
+// summarizing it.
+type ColumnSummary struct {
+	Name string
+	sample.Summary
+	Err error
+}
+
+// Summarize describes every column of the table with sample.Describe,
+// using the given confidence level.
+func (t *Table) Summarize(confidence float64) []ColumnSummary {
+	summaries := make([]ColumnSummary, len(t.Columns))
+	for i, c := range t.Columns {
+		s, err := sample.Describe(c.Values, confidence)
+		summaries[i] = ColumnSummary{Name: c.Name, Summary: s, Err: err}
+	}
+	return summaries
+}
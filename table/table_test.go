@@ -0,0 +1,232 @@
+package table
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alcortesm/sample"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description    string
+		input          string
+		opts           Options
+		wantColumns    []Column
+		wantErrors     []string
+		wantNonNumeric []string
+		// the first error of each non numeric column
+		wantNonNumericErrors []string
+	}{
+		{
+			description: "every numeric column",
+			input: "host,latency,size\n" +
+				"a,1.5,10\n" +
+				"b,2.5,NA\n" +
+				"c,,30\n",
+			opts: Options{Header: true},
+			wantColumns: []Column{
+				{Name: "latency", Values: []float64{1.5, 2.5}},
+				{Name: "size", Values: []float64{10, 30}},
+			},
+			wantNonNumeric: []string{"host"},
+			wantNonNumericErrors: []string{
+				`line 2, column "host": invalid number "a"`,
+			},
+		}, {
+			description: "typo in a numeric column",
+			input: "a,b\n" +
+				"1,2\n" +
+				"3,4\n" +
+				"5,6x\n" +
+				"7,x\n",
+			opts: Options{Header: true},
+			wantColumns: []Column{
+				{Name: "a", Values: []float64{1, 3, 5, 7}},
+			},
+			wantNonNumeric: []string{"b"},
+			wantNonNumericErrors: []string{
+				`line 4, column "b": invalid number "6x"`,
+			},
+		}, {
+			description: "no header",
+			input:       "1,2\n3,4\n",
+			wantColumns: []Column{
+				{Name: "0", Values: []float64{1, 3}},
+				{Name: "1", Values: []float64{2, 4}},
+			},
+		}, {
+			description: "selected by name and index",
+			input: "a,b,c\n" +
+				"1,2,3\n" +
+				"4,5,6\n",
+			opts: Options{Header: true, Names: []string{"c"}, Indexes: []int{0}},
+			wantColumns: []Column{
+				{Name: "a", Values: []float64{1, 4}},
+				{Name: "c", Values: []float64{3, 6}},
+			},
+		}, {
+			description: "row errors in selected columns",
+			input: "a,b\n" +
+				"1,x\n" +
+				"oops,2\n" +
+				"3\n",
+			opts: Options{Header: true, Names: []string{"a", "b"}},
+			wantColumns: []Column{
+				{Name: "a", Values: []float64{1, 3}},
+				{Name: "b", Values: []float64{2}},
+			},
+			wantErrors: []string{
+				`line 2, column "b": invalid number "x"`,
+				`line 3, column "a": invalid number "oops"`,
+				`line 4, column "b": missing field`,
+			},
+		}, {
+			description: "tsv with decimal comma",
+			input: "a\tb\n" +
+				"1.234,5\t0,25\n" +
+				"-\t1,5\n",
+			opts: Options{Comma: '\t', Header: true, Decimal: ',', Thousands: '.'},
+			wantColumns: []Column{
+				{Name: "a", Values: []float64{1234.5}},
+				{Name: "b", Values: []float64{0.25, 1.5}},
+			},
+		}, {
+			description: "custom missing tokens",
+			input:       "a\n1\n?\nNA\n",
+			opts:        Options{Header: true, Missing: []string{"?"}, Names: []string{"a"}},
+			wantColumns: []Column{
+				{Name: "a", Values: []float64{1}},
+			},
+			wantErrors: []string{
+				`line 4, column "a": invalid number "NA"`,
+			},
+		}, {
+			description: "empty input",
+			input:       "",
+			wantColumns: []Column{},
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			got, err := Read(strings.NewReader(test.input), test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Columns, test.wantColumns) {
+				t.Errorf("wrong columns:\nwant %v\ngot  %v", test.wantColumns, got.Columns)
+			}
+			errs := []string{}
+			for _, e := range got.Errors {
+				errs = append(errs, e.Error())
+			}
+			if len(errs) != 0 || len(test.wantErrors) != 0 {
+				if !reflect.DeepEqual(errs, test.wantErrors) {
+					t.Errorf("wrong errors:\nwant %q\ngot  %q", test.wantErrors, errs)
+				}
+			}
+			if !reflect.DeepEqual(got.NonNumeric, test.wantNonNumeric) {
+				t.Errorf("wrong non numeric columns: want %q, got %q",
+					test.wantNonNumeric, got.NonNumeric)
+			}
+			nonNumericErrs := []string{}
+			for _, e := range got.NonNumericErrors {
+				nonNumericErrs = append(nonNumericErrs, e.Error())
+			}
+			if len(nonNumericErrs) != 0 || len(test.wantNonNumericErrors) != 0 {
+				if !reflect.DeepEqual(nonNumericErrs, test.wantNonNumericErrors) {
+					t.Errorf("wrong non numeric errors:\nwant %q\ngot  %q",
+						test.wantNonNumericErrors, nonNumericErrs)
+				}
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		input       string
+		opts        Options
+		want        string
+	}{
+		{
+			description: "unknown name",
+			input:       "a,b\n1,2\n",
+			opts:        Options{Header: true, Names: []string{"c"}},
+			want:        `column "c" not found`,
+		}, {
+			description: "index out of range",
+			input:       "1,2\n",
+			opts:        Options{Indexes: []int{2}},
+			want:        "column index 2 out of range",
+		}, {
+			description: "invalid csv",
+			input:       "a,\"b\n",
+			want:        "extraneous or missing \" in quoted-field",
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := Read(strings.NewReader(test.input), test.opts)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("cannot find %q in %q", test.want, err)
+			}
+		})
+	}
+}
+
+func TestTableColumn(t *testing.T) {
+	t.Parallel()
+	table, err := Read(strings.NewReader("a,b\n1,2\n3,4\n"), Options{Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := table.Column("b")
+	if b == nil {
+		t.Fatal("column b not found")
+	}
+	mean, err := sample.Mean(b.Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mean != 3 {
+		t.Errorf("wrong mean: want 3, got %f", mean)
+	}
+	if table.Column("c") != nil {
+		t.Error("unexpected column c")
+	}
+}
+
+func TestTableSummarize(t *testing.T) {
+	t.Parallel()
+	table, err := Read(strings.NewReader("a,b\n2,1\n3,NA\n5,NA\n6,NA\n9,NA\n"),
+		Options{Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := table.Summarize(0.95)
+	if len(got) != 2 {
+		t.Fatalf("want 2 summaries, got %d", len(got))
+	}
+
+	want, err := sample.Describe([]float64{2, 3, 5, 6, 9}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Name != "a" || got[0].Err != nil || got[0].Summary != want {
+		t.Errorf("wrong summary of a: want %+v, got %+v", want, got[0])
+	}
+	if got[1].Name != "b" || !errors.Is(got[1].Err, sample.ErrSampleTooSmall) {
+		t.Errorf("wrong summary of b: want error %q, got %+v", sample.ErrSampleTooSmall, got[1])
+	}
+}
@@ -13,9 +13,13 @@ import (
 // approximation to 'd' and higer closest approximaiton of 'c' in the
 // table (this is, it returns a conservative approximation for values
 // not present in the table).
+//
+// If 'c' is bigger than the highest confidence level in the table, it
+// returns ErrUnsupportedConfidence, and if 'd' is less than 1,
+// ErrSampleTooSmall; both wrapped with the offending value.
 func studentTwoSidedCriticalValue(d int64, c float64) (float64, error) {
-	if c <= 0.0 || c >= 1.0 {
-		return 0.0, ErrInvalidConfidence
+	if err := checkConfidence(c); err != nil {
+		return 0.0, err
 	}
 
 	type resultAndError struct {
@@ -29,15 +33,14 @@ func studentTwoSidedCriticalValue(d int64, c float64) (float64, error) {
 	}()
 
 	confidenceIndex, err := indexOfEqualOrClosestHigher(c, percentile)
+	degree := <-ch
 	if err != nil {
-		return 0.0, fmt.Errorf(
-			"cannot find suitable confidence level: %v", err)
+		return 0.0, fmt.Errorf("%w: %v, the highest supported is %v",
+			ErrUnsupportedConfidence, c, percentile[len(percentile)-1])
 	}
-
-	degree := <-ch
 	if degree.err != nil {
-		return 0.0, fmt.Errorf(
-			"cannot find suitable degree of freedom: %v", degree.err)
+		return 0.0, fmt.Errorf("%w: %d degrees of freedom",
+			ErrSampleTooSmall, d)
 	}
 
 	return tTable[degree.index][confidenceIndex], nil
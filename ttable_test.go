@@ -1,6 +1,7 @@
 package sample
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -318,7 +319,7 @@ func TestStudentTwoSidedCriticalValueErrorsInvalidConfidence(t *testing.T) {
 			if err == nil {
 				t.Fatal("unexpected success")
 			}
-			if err != ErrInvalidConfidence {
+			if !errors.Is(err, ErrInvalidConfidence) {
 				t.Errorf("want: %q, got: %q", ErrInvalidConfidence, err)
 			}
 		})
@@ -327,7 +328,24 @@ func TestStudentTwoSidedCriticalValueErrorsInvalidConfidence(t *testing.T) {
 
 func TestStudentTwoSidedCriticalValueErrorLowFreedomDegree(t *testing.T) {
 	t.Parallel()
-	if _, err := studentTwoSidedCriticalValue(0, 0.50); err == nil {
-		t.Errorf("unexpected success")
+	_, err := studentTwoSidedCriticalValue(0, 0.50)
+	if !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want: %q, got: %v", ErrSampleTooSmall, err)
+	}
+}
+
+// Valid confidence levels beyond the table are not invalid ones.
+func TestStudentTwoSidedCriticalValueErrorUnsupportedConfidence(t *testing.T) {
+	t.Parallel()
+	for _, confidence := range []float64{0.9995, 0.999999} {
+		_, err := studentTwoSidedCriticalValue(10, confidence)
+		if !errors.Is(err, ErrUnsupportedConfidence) ||
+			errors.Is(err, ErrInvalidConfidence) {
+			t.Errorf("%v: want %q, got %v",
+				confidence, ErrUnsupportedConfidence, err)
+		}
+	}
+	if _, err := studentTwoSidedCriticalValue(10, 0.999); err != nil {
+		t.Errorf("highest level in the table: %v", err)
 	}
 }
@@ -0,0 +1,157 @@
+package sample
+
+import "math"
+
+// FTest performs the two-sample F-test for the equality of variances,
+// testing the null hypothesis that both samples come from Normal
+// populations with the same variance. The statistic is the ratio of the
+// variance of a to the variance of b and the p-value is two-sided.
+//
+// The F-test is very sensitive to non-normality, prefer Levene or
+// BrownForsythe when the populations may not be Normal.
+//
+// If the sample size of a or b is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the standard deviation of b is zero, it returns ErrZeroVariance.
+func FTest(a, b []float64) (ANOVAResult, error) {
+	summaries, err := summarizeGroups("FTest", [][]float64{a, b})
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+	if summaries[1].StandardDeviation == 0.0 {
+		return ANOVAResult{}, ErrZeroVariance
+	}
+
+	ratio := summaries[0].StandardDeviation / summaries[1].StandardDeviation
+	f := ratio * ratio
+	df1 := float64(len(a) - 1)
+	df2 := float64(len(b) - 1)
+
+	upper := fSurvival(f, df1, df2)
+	pValue := 2.0 * math.Min(upper, 1.0-upper)
+
+	return ANOVAResult{
+		Statistic: f,
+		DF1:       df1,
+		DF2:       df2,
+		PValue:    math.Min(pValue, 1.0),
+		Groups:    summaries,
+	}, nil
+}
+
+// Levene performs Levene's test for the equality of variances, testing
+// the null hypothesis that all the groups come from populations with
+// the same variance. It is a one-way ANOVA of the absolute deviations of
+// the sample points from the mean of their group.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If all the absolute deviations are equal, it returns ErrZeroVariance.
+func Levene(groups [][]float64) (ANOVAResult, error) {
+	return absoluteDeviationsANOVA("Levene", groups, func(g []float64) float64 {
+		mean, _ := Mean(g)
+		return mean
+	})
+}
+
+// BrownForsythe performs the Brown–Forsythe test for the equality of
+// variances. It is like Levene, but uses the deviations from the median
+// of each group instead of from its mean, which makes it more robust
+// when the populations are skewed or heavy-tailed.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If all the absolute deviations are equal, it returns ErrZeroVariance.
+func BrownForsythe(groups [][]float64) (ANOVAResult, error) {
+	return absoluteDeviationsANOVA("BrownForsythe", groups, median)
+}
+
+func absoluteDeviationsANOVA(
+	fn string,
+	groups [][]float64,
+	center func([]float64) float64,
+) (ANOVAResult, error) {
+	summaries, err := summarizeGroups(fn, groups)
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+
+	deviations := make([][]float64, len(groups))
+	allEqual := true
+	for i, g := range groups {
+		c := center(g)
+		deviations[i] = make([]float64, len(g))
+		for j, samplePoint := range g {
+			deviations[i][j] = math.Abs(samplePoint - c)
+			allEqual = allEqual && deviations[i][j] == deviations[0][0]
+		}
+	}
+	// the F statistic would be 0/0
+	if allEqual {
+		return ANOVAResult{}, ErrZeroVariance
+	}
+
+	result, err := OneWayANOVA(deviations)
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+	result.Groups = summaries
+
+	return result, nil
+}
+
+// Bartlett performs Bartlett's test for the equality of variances,
+// testing the null hypothesis that all the groups come from Normal
+// populations with the same variance. It is more powerful than Levene
+// for Normal populations, but very sensitive to non-normality.
+//
+// If there are less than 2 groups, it returns ErrTooFewGroups.
+//
+// If the sample size of any group is less than 2, it returns
+// ErrSampleTooSmall.
+//
+// If the standard deviation of any group is zero, it returns
+// ErrZeroVariance.
+func Bartlett(groups [][]float64) (ANOVAResult, error) {
+	summaries, err := summarizeGroups("Bartlett", groups)
+	if err != nil {
+		return ANOVAResult{}, err
+	}
+
+	total := 0.0
+	pooled := 0.0
+	sumLogs := 0.0
+	sumInverses := 0.0
+	var df, variance float64
+	for _, s := range summaries {
+		if s.StandardDeviation == 0.0 {
+			return ANOVAResult{}, ErrZeroVariance
+		}
+		df = float64(s.N - 1)
+		variance = s.StandardDeviation * s.StandardDeviation
+		total += df
+		pooled += df * variance
+		sumLogs += df * math.Log(variance)
+		sumInverses += 1.0 / df
+	}
+	pooled /= total
+
+	k := float64(len(summaries))
+	numerator := total*math.Log(pooled) - sumLogs
+	denominator := 1.0 + (sumInverses-1.0/total)/(3.0*(k-1.0))
+	chi2 := numerator / denominator
+
+	return ANOVAResult{
+		Statistic: chi2,
+		DF1:       k - 1.0,
+		PValue:    chiSquareSurvival(chi2, k-1.0),
+		Groups:    summaries,
+	}, nil
+}
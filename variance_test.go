@@ -0,0 +1,127 @@
+package sample
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFTest(t *testing.T) {
+	t.Parallel()
+	got, err := FTest(anovaGroups[0], anovaGroups[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 0.66667,
+		DF1:       5,
+		DF2:       5,
+		PValue:    0.66722,
+	})
+
+	// swapping the samples inverts the statistic but not the p-value
+	swapped, err := FTest(anovaGroups[1], anovaGroups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, swapped, ANOVAResult{
+		Statistic: 1.5,
+		DF1:       5,
+		DF2:       5,
+		PValue:    0.66722,
+	})
+}
+
+func TestLevene(t *testing.T) {
+	t.Parallel()
+	got, err := Levene(anovaGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 0.6,
+		DF1:       2,
+		DF2:       15,
+		PValue:    0.56146,
+	})
+	if len(got.Groups) != 3 || got.Groups[0].Mean != 5 {
+		t.Errorf("groups must describe the original data, got %v", got.Groups)
+	}
+}
+
+func TestBrownForsythe(t *testing.T) {
+	t.Parallel()
+	got, err := BrownForsythe(anovaGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 0.50847,
+		DF1:       2,
+		DF2:       15,
+		PValue:    0.61141,
+	})
+}
+
+func TestBartlett(t *testing.T) {
+	t.Parallel()
+	got, err := Bartlett(anovaGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkANOVAResult(t, got, ANOVAResult{
+		Statistic: 0.36661,
+		DF1:       2,
+		PValue:    0.83251,
+	})
+}
+
+func TestVarianceTestsErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		groups      [][]float64
+		want        error
+	}{
+		{
+			description: "one group",
+			groups:      [][]float64{{1, 2, 3}},
+			want:        ErrTooFewGroups,
+		}, {
+			description: "small group",
+			groups:      [][]float64{{1, 2, 3}, {1}},
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "constant groups",
+			groups:      [][]float64{{1, 1}, {2, 2}},
+			want:        ErrZeroVariance,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			for name, f := range map[string]func([][]float64) (ANOVAResult, error){
+				"Levene":        Levene,
+				"BrownForsythe": BrownForsythe,
+				"Bartlett":      Bartlett,
+			} {
+				_, err := f(test.groups)
+				if err == nil {
+					t.Fatalf("%s: unexpected success", name)
+				}
+				if !errors.Is(err, test.want) {
+					t.Errorf("%s: want %q, got %q", name, test.want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFTestErrors(t *testing.T) {
+	t.Parallel()
+	if _, err := FTest([]float64{1}, []float64{1, 2}); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	if _, err := FTest([]float64{1, 2}, []float64{3, 3}); !errors.Is(err, ErrZeroVariance) {
+		t.Errorf("want %q, got %v", ErrZeroVariance, err)
+	}
+}
@@ -0,0 +1,244 @@
+package sample
+
+import "math"
+
+// Weighting is the meaning of the weights of a weighted sample, which
+// changes how its variance is estimated.
+type Weighting int
+
+// FrequencyWeights are counts: a sample point with weight w stands for
+// w identical observations, so the results are the same as those of
+// the expanded sample and the sample size is the sum of the weights.
+//
+// ReliabilityWeights are relative precisions, typically the inverse of
+// the variance of each sample point, only their proportions matter.
+//
+// SurveyWeights are inverse probabilities of selection, each sample
+// point stands for w members of the population; the standard error of
+// the mean is the linearized (sandwich) one used by survey software.
+const (
+	FrequencyWeights Weighting = iota
+	ReliabilityWeights
+	SurveyWeights
+)
+
+var weightingNames = map[Weighting]string{
+	FrequencyWeights:   "frequency",
+	ReliabilityWeights: "reliability",
+	SurveyWeights:      "survey",
+}
+
+func (w Weighting) String() string {
+	if name, ok := weightingNames[w]; ok {
+		return name
+	}
+	return "unknown weighting"
+}
+
+// The sums needed by the weighted estimators.
+type weightedSums struct {
+	v1       float64 // sum of the weights
+	v2       float64 // sum of the squared weights
+	mean     float64
+	ss       float64 // sum of w_i (x_i - mean)^2
+	ss2      float64 // sum of w_i^2 (x_i - mean)^2
+	positive int     // number of sample points with non-zero weight
+}
+
+// Validates the weights and computes the weighted sums, at least one
+// weight must be positive; fn is the function reported in the sample
+// size errors.
+func newWeightedSums(fn string, data, weights []float64) (weightedSums, error) {
+	if len(weights) != len(data) {
+		return weightedSums{}, ErrInvalidWeights
+	}
+
+	s := weightedSums{}
+	for i, w := range weights {
+		if !(w >= 0.0) || math.IsInf(w, 1) {
+			return weightedSums{}, ErrInvalidWeights
+		}
+		if w > 0.0 {
+			s.positive++
+		}
+		s.v1 += w
+		s.v2 += w * w
+		s.mean += w * data[i]
+	}
+	if err := checkSampleSize(fn, s.positive, 1); err != nil {
+		return weightedSums{}, err
+	}
+	s.mean /= s.v1
+
+	for i, w := range weights {
+		diff := data[i] - s.mean
+		s.ss += w * diff * diff
+		s.ss2 += w * w * diff * diff
+	}
+
+	return s, nil
+}
+
+// Returns the estimation of the variance of the population.
+func (s weightedSums) variance(fn string, weighting Weighting) (float64, error) {
+	switch weighting {
+	case FrequencyWeights:
+		if err := checkSampleSize(fn, int(s.v1), 2); err != nil {
+			return 0.0, err
+		}
+		return s.ss / (s.v1 - 1.0), nil
+	case ReliabilityWeights:
+		if err := checkSampleSize(fn, s.positive, 2); err != nil {
+			return 0.0, err
+		}
+		return s.ss / (s.v1 - s.v2/s.v1), nil
+	case SurveyWeights:
+		if err := checkSampleSize(fn, s.positive, 2); err != nil {
+			return 0.0, err
+		}
+		n := float64(s.positive)
+		return s.ss / s.v1 * n / (n - 1.0), nil
+	default:
+		return 0.0, ErrUnknownWeighting
+	}
+}
+
+// Returns the standard error of the weighted mean and its degrees of
+// freedom.
+func (s weightedSums) standardError(fn string, weighting Weighting) (se, df float64, err error) {
+	variance, err := s.variance(fn, weighting)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+
+	kish := s.v1 * s.v1 / s.v2
+	switch weighting {
+	case FrequencyWeights:
+		return math.Sqrt(variance / s.v1), s.v1 - 1.0, nil
+	case ReliabilityWeights:
+		return math.Sqrt(variance / kish), kish - 1.0, nil
+	default:
+		n := float64(s.positive)
+		return math.Sqrt(n/(n-1.0)*s.ss2) / s.v1, kish - 1.0, nil
+	}
+}
+
+// EffectiveSampleSize returns Kish's effective sample size of a sample
+// with the given weights: (sum w_i)^2 / sum w_i^2. It is the size of an
+// unweighted sample giving a mean of the same precision.
+//
+// If no weight is positive, it returns ErrSampleTooSmall.
+//
+// If any weight is negative or not finite, it returns
+// ErrInvalidWeights.
+func EffectiveSampleSize(weights []float64) (float64, error) {
+	s, err := newWeightedSums("EffectiveSampleSize", weights, weights)
+	if err != nil {
+		return 0.0, err
+	}
+	return s.v1 * s.v1 / s.v2, nil
+}
+
+// WeightedMean computes the weighted mean of a sample: sum w_i x_i /
+// sum w_i. It is the same for all kinds of weights.
+//
+// If no weight is positive, it returns ErrSampleTooSmall.
+//
+// If the number of weights is not the sample size, or any weight is
+// negative or not finite, it returns ErrInvalidWeights.
+func WeightedMean(data, weights []float64) (float64, error) {
+	s, err := newWeightedSums("WeightedMean", data, weights)
+	if err != nil {
+		return 0.0, err
+	}
+	return s.mean, nil
+}
+
+// WeightedStandardDeviation computes the estimation of the standard
+// deviation of a population from a weighted sample. With all weights
+// equal to 1, it is the same as StandardDeviation for all kinds of
+// weights.
+//
+// The variance is sum w_i (x_i - mean)^2 divided by sum w_i - 1 for
+// FrequencyWeights, by sum w_i - sum w_i^2 / sum w_i for
+// ReliabilityWeights, and by sum w_i * (n-1) / n for SurveyWeights,
+// where n is the number of sample points with non-zero weight.
+//
+// If the sum of FrequencyWeights is less than 2, or less than 2 sample
+// points have non-zero ReliabilityWeights or SurveyWeights, it returns
+// ErrSampleTooSmall.
+//
+// If the number of weights is not the sample size, or any weight is
+// negative or not finite, it returns ErrInvalidWeights.
+//
+// If the kind of weights is not known, it returns ErrUnknownWeighting.
+func WeightedStandardDeviation(data, weights []float64, weighting Weighting) (float64, error) {
+	const fn = "WeightedStandardDeviation"
+	s, err := newWeightedSums(fn, data, weights)
+	if err != nil {
+		return 0.0, err
+	}
+	variance, err := s.variance(fn, weighting)
+	if err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(variance), nil
+}
+
+// WeightedStandardError returns the standard error of the weighted
+// mean of a sample. With all weights equal to 1, it is the same as
+// StandardError for all kinds of weights.
+//
+// For FrequencyWeights it is the standard deviation divided by the
+// square root of the sum of the weights, for ReliabilityWeights it is
+// divided by the square root of the effective sample size instead. For
+// SurveyWeights it is sqrt(n/(n-1) sum w_i^2 (x_i - mean)^2) / sum w_i.
+//
+// It returns the same errors as WeightedStandardDeviation.
+func WeightedStandardError(data, weights []float64, weighting Weighting) (float64, error) {
+	const fn = "WeightedStandardError"
+	s, err := newWeightedSums(fn, data, weights)
+	if err != nil {
+		return 0.0, err
+	}
+	se, _, err := s.standardError(fn, weighting)
+	return se, err
+}
+
+// WeightedMeanConfidenceIntervals calculates the confidence intervals of
+// the mean of a population from a weighted sample, using the Student-t
+// distribution like MeanConfidenceIntervals.
+//
+// The degrees of freedom are the sum of the weights minus 1 for
+// FrequencyWeights, and the effective sample size minus 1 for the
+// other kinds of weights, see EffectiveSampleSize. As they are not
+// always integers, the critical values of the t distribution are
+// computed instead of looked up in a table, so with all weights equal
+// to 1 the intervals are slightly narrower than the conservative ones
+// of MeanConfidenceIntervals.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence; otherwise it returns the same errors as
+// WeightedStandardDeviation.
+func WeightedMeanConfidenceIntervals(
+	data, weights []float64,
+	weighting Weighting,
+	confidence float64,
+) ([2]float64, error) {
+	const fn = "WeightedMeanConfidenceIntervals"
+	s, err := newWeightedSums(fn, data, weights)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	se, df, err := s.standardError(fn, weighting)
+	if err != nil {
+		return [2]float64{}, err
+	}
+
+	if err := checkConfidence(confidence); err != nil {
+		return [2]float64{}, err
+	}
+	margin := studentTQuantile(1.0-(1.0-confidence)/2.0, df) * se
+
+	return [2]float64{s.mean - margin, s.mean + margin}, nil
+}
@@ -0,0 +1,219 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var (
+	weightedData    = []float64{1, 2, 3, 4, 5}
+	weightedWeights = []float64{1, 2, 1, 3, 1}
+)
+
+func TestWeightedMean(t *testing.T) {
+	t.Parallel()
+	got, err := WeightedMean(weightedData, weightedWeights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(got, 3.125, tolerance/100) {
+		t.Errorf("want 3.125, got %f", got)
+	}
+
+	// zero weights ignore the sample point
+	got, err = WeightedMean([]float64{1, 1000, 3}, []float64{1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 2 {
+		t.Errorf("want 2, got %f", got)
+	}
+}
+
+func TestEffectiveSampleSize(t *testing.T) {
+	t.Parallel()
+	got, err := EffectiveSampleSize(weightedWeights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equals(got, 4, tolerance/100) {
+		t.Errorf("want 4, got %f", got)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		weighting Weighting
+		sd        float64
+		se        float64
+		interval  [2]float64
+	}{
+		{
+			// the same as the expanded sample, with 8 points
+			weighting: FrequencyWeights,
+			sd:        1.356203,
+			se:        0.479490,
+			interval:  [2]float64{1.991186, 4.258814},
+		}, {
+			weighting: ReliabilityWeights,
+			sd:        1.464866,
+			se:        0.732433,
+			interval:  [2]float64{0.794071, 5.455929},
+		}, {
+			weighting: SurveyWeights,
+			sd:        1.418351,
+			se:        0.625,
+			interval:  [2]float64{1.135971, 5.114029},
+		},
+	} {
+		test := test
+		t.Run(test.weighting.String(), func(t *testing.T) {
+			t.Parallel()
+			sd, err := WeightedStandardDeviation(weightedData, weightedWeights, test.weighting)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equals(sd, test.sd, tolerance/100) {
+				t.Errorf("wrong standard deviation: want %f, got %f", test.sd, sd)
+			}
+
+			se, err := WeightedStandardError(weightedData, weightedWeights, test.weighting)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equals(se, test.se, tolerance/100) {
+				t.Errorf("wrong standard error: want %f, got %f", test.se, se)
+			}
+
+			interval, err := WeightedMeanConfidenceIntervals(
+				weightedData, weightedWeights, test.weighting, 0.95)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pairEquals(interval, test.interval, tolerance/100) {
+				t.Errorf("wrong interval: want %f, got %f", test.interval, interval)
+			}
+		})
+	}
+}
+
+func TestWeightedUnitWeights(t *testing.T) {
+	t.Parallel()
+	data := []float64{2, 3, 5, 6, 9}
+	ones := []float64{1, 1, 1, 1, 1}
+	wantSD, _ := StandardDeviation(data)
+	wantSE, _ := StandardError(data)
+	wantInterval, _ := MeanConfidenceIntervals(data, 0.95)
+
+	for weighting := range weightingNames {
+		sd, err := WeightedStandardDeviation(data, ones, weighting)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equals(sd, wantSD, tolerance/100) {
+			t.Errorf("%s: wrong standard deviation: want %f, got %f", weighting, wantSD, sd)
+		}
+
+		se, err := WeightedStandardError(data, ones, weighting)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equals(se, wantSE, tolerance/100) {
+			t.Errorf("%s: wrong standard error: want %f, got %f", weighting, wantSE, se)
+		}
+
+		// the table of MeanConfidenceIntervals has only 3 decimals
+		interval, err := WeightedMeanConfidenceIntervals(data, ones, weighting, 0.95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pairEquals(interval, wantInterval, tolerance) {
+			t.Errorf("%s: wrong interval: want %f, got %f", weighting, wantInterval, interval)
+		}
+	}
+}
+
+func TestWeightedErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		description string
+		data        []float64
+		weights     []float64
+		weighting   Weighting
+		confidence  float64
+		want        error
+	}{
+		{
+			description: "different lengths",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{1, 2},
+			want:        ErrInvalidWeights,
+		}, {
+			description: "negative weight",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{1, -2, 1},
+			want:        ErrInvalidWeights,
+		}, {
+			description: "NaN weight",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{1, math.NaN(), 1},
+			want:        ErrInvalidWeights,
+		}, {
+			description: "all weights zero",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{0, 0, 0},
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "a single frequency",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{0, 1, 0},
+			weighting:   FrequencyWeights,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "a single reliability weight",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{0, 5, 0},
+			weighting:   ReliabilityWeights,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "a single survey weight",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{0, 5, 0},
+			weighting:   SurveyWeights,
+			want:        ErrSampleTooSmall,
+		}, {
+			description: "unknown weighting",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{1, 1, 1},
+			weighting:   Weighting(42),
+			want:        ErrUnknownWeighting,
+		}, {
+			description: "invalid confidence",
+			data:        []float64{1, 2, 3},
+			weights:     []float64{1, 1, 1},
+			confidence:  1,
+			want:        ErrInvalidConfidence,
+		},
+	} {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+			confidence := test.confidence
+			if confidence == 0 {
+				confidence = 0.95
+			}
+			_, err := WeightedMeanConfidenceIntervals(
+				test.data, test.weights, test.weighting, confidence)
+			if !errors.Is(err, test.want) {
+				t.Errorf("want %q, got %v", test.want, err)
+			}
+		})
+	}
+
+	// a frequency weight of 5 stands for 5 sample points
+	if _, err := WeightedStandardDeviation([]float64{1, 2}, []float64{0, 5}, FrequencyWeights); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package sample
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Window computes statistics over a sliding window of a stream of
+// sample points: either the last N sample points, see NewWindow, or the
+// ones taken during the last duration, see NewTimeWindow.
+//
+// Adding and expiring sample points take amortized constant time, and
+// so do the queries, which give the same results as the functions of
+// the package on the sample points in the window, up to rounding
+// errors.
+//
+// A Window is safe for concurrent use. The zero value is not usable,
+// use NewWindow or NewTimeWindow to create one.
+type Window struct {
+	mu sync.Mutex
+
+	size int           // of count based windows
+	span time.Duration // of time based windows
+	now  func() time.Time
+
+	// sample points in the window, from points[head], sorted by time
+	points  []windowPoint
+	head    int
+	moments moments
+	removed int // since the moments were last recomputed
+}
+
+type windowPoint struct {
+	x float64
+	t time.Time
+}
+
+// NewWindow returns a window over the last size sample points added.
+//
+// If size is not positive, it returns ErrInvalidWindow.
+func NewWindow(size int) (*Window, error) {
+	if size < 1 {
+		return nil, ErrInvalidWindow
+	}
+	return &Window{size: size, now: time.Now}, nil
+}
+
+// NewTimeWindow returns a window over the sample points taken during
+// the last span of time, up to the current time.
+//
+// If span is not positive, it returns ErrInvalidWindow.
+func NewTimeWindow(span time.Duration) (*Window, error) {
+	if span <= 0 {
+		return nil, ErrInvalidWindow
+	}
+	return &Window{span: span, now: time.Now}, nil
+}
+
+// Add adds a sample point taken now. NaNs are ignored.
+func (w *Window) Add(x float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.add(x, w.now())
+}
+
+// AddAt adds a sample point taken at time t, which is only relevant for
+// time based windows: sample points taken before the start of the
+// window are ignored. Sample points do not need to be added in order,
+// but adding one takes time proportional to the number of sample points
+// in the window taken after it. NaNs are ignored.
+func (w *Window) AddAt(x float64, t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.add(x, t)
+}
+
+func (w *Window) add(x float64, t time.Time) {
+	if math.IsNaN(x) {
+		return
+	}
+
+	if w.size > 0 {
+		if len(w.points)-w.head == w.size {
+			w.removeOldest()
+		}
+		w.points = append(w.points, windowPoint{x: x, t: t})
+		w.moments.add(x)
+		return
+	}
+
+	w.expire()
+	if t.Before(w.now().Add(-w.span)) {
+		return
+	}
+
+	// keep the points sorted by time, inserting from the end
+	i := len(w.points)
+	w.points = append(w.points, windowPoint{})
+	for i > w.head && t.Before(w.points[i-1].t) {
+		w.points[i] = w.points[i-1]
+		i--
+	}
+	w.points[i] = windowPoint{x: x, t: t}
+	w.moments.add(x)
+}
+
+// Removes the points of time based windows taken before the start of
+// the window.
+func (w *Window) expire() {
+	if w.span == 0 {
+		return
+	}
+	start := w.now().Add(-w.span)
+	for w.head < len(w.points) && w.points[w.head].t.Before(start) {
+		w.removeOldest()
+	}
+}
+
+func (w *Window) removeOldest() {
+	w.moments.remove(w.points[w.head].x)
+	w.points[w.head] = windowPoint{}
+	w.head++
+	w.removed++
+
+	n := len(w.points) - w.head
+	// reclaim the space of the removed points
+	if w.head > n {
+		copy(w.points, w.points[w.head:])
+		w.points = w.points[:n]
+		w.head = 0
+	}
+	// removing sample points accumulates rounding errors
+	if w.removed > n && w.removed >= 64 {
+		w.moments = moments{}
+		for _, p := range w.points[w.head:] {
+			w.moments.add(p.x)
+		}
+		w.removed = 0
+	}
+}
+
+// N returns the number of sample points in the window.
+func (w *Window) N() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire()
+	return w.moments.n
+}
+
+// Values returns a copy of the sample points in the window, from the
+// oldest to the newest.
+func (w *Window) Values() []float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire()
+	values := make([]float64, 0, len(w.points)-w.head)
+	for _, p := range w.points[w.head:] {
+		values = append(values, p.x)
+	}
+	return values
+}
+
+// Mean computes the sample mean of the sample points in the window.
+//
+// If the window is empty, it returns ErrSampleTooSmall.
+func (w *Window) Mean() (float64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire()
+	if err := checkSampleSize("Window.Mean", w.moments.n, 1); err != nil {
+		return 0.0, err
+	}
+	return w.moments.mean, nil
+}
+
+// StandardDeviation computes the sample-based unbiased estimation of
+// the standard deviation of the population from the sample points in
+// the window.
+//
+// If the window has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+func (w *Window) StandardDeviation() (float64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire()
+	if err := checkSampleSize("Window.StandardDeviation", w.moments.n, 2); err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(w.moments.variance()), nil
+}
+
+// StandardError returns the standard error of the mean of the sample
+// points in the window.
+//
+// If the window has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+func (w *Window) StandardError() (float64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire()
+	if err := checkSampleSize("Window.StandardError", w.moments.n, 2); err != nil {
+		return 0.0, err
+	}
+	return math.Sqrt(w.moments.variance() / float64(w.moments.n)), nil
+}
+
+// MeanConfidenceIntervals calculates the confidence intervals of the
+// mean from the sample points in the window, like
+// MeanConfidenceIntervals.
+//
+// If the window has less than 2 sample points, it returns
+// ErrSampleTooSmall.
+//
+// If the confidence value is not in the ]0, 1[ it returns
+// ErrInvalidConfidence.
+func (w *Window) MeanConfidenceIntervals(confidence float64) ([2]float64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire()
+	return w.moments.meanConfidenceIntervals("Window.MeanConfidenceIntervals", confidence)
+}
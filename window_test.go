@@ -0,0 +1,205 @@
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Checks the statistics of the window are the ones of the slice
+// functions on want.
+func checkWindow(t *testing.T, w *Window, want []float64) {
+	t.Helper()
+	if w.N() != len(want) {
+		t.Fatalf("wrong N: want %d, got %d", len(want), w.N())
+	}
+	values := w.Values()
+	for i := range want {
+		if values[i] != want[i] {
+			t.Fatalf("wrong values: want %v, got %v", want, values)
+		}
+	}
+	if len(want) < 2 {
+		return
+	}
+
+	mean, _ := w.Mean()
+	wantMean, _ := Mean(want)
+	sd, _ := w.StandardDeviation()
+	wantSD, _ := StandardDeviation(want)
+	se, _ := w.StandardError()
+	wantSE, _ := StandardError(want)
+	interval, err := w.MeanConfidenceIntervals(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantInterval, _ := MeanConfidenceIntervals(want, 0.95)
+
+	const tolerance = 1e-6
+	if !equals(mean, wantMean, tolerance) ||
+		!equals(sd, wantSD, tolerance) ||
+		!equals(se, wantSE, tolerance) ||
+		!pairEquals(interval, wantInterval, tolerance) {
+		t.Fatalf("want %f, %f, %f, %f; got %f, %f, %f, %f",
+			wantMean, wantSD, wantSE, wantInterval, mean, sd, se, interval)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	t.Parallel()
+	w, err := NewWindow(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	data := []float64{}
+	for i := 0; i < 1000; i++ {
+		x := 1e6 + rng.NormFloat64()
+		data = append(data, x)
+		w.Add(x)
+		if len(data) > 10 {
+			data = data[1:]
+		}
+		checkWindow(t, w, data)
+	}
+	w.Add(math.NaN())
+	checkWindow(t, w, data)
+}
+
+// a clock for the tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestTimeWindow(t *testing.T) {
+	t.Parallel()
+	w, err := NewTimeWindow(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w.now = clock.Now
+	start := clock.now
+
+	w.Add(1)
+	clock.now = start.Add(20 * time.Second)
+	w.Add(2)
+	clock.now = start.Add(40 * time.Second)
+	w.Add(3)
+	checkWindow(t, w, []float64{1, 2, 3})
+
+	// out of order
+	w.AddAt(4, start.Add(30*time.Second))
+	checkWindow(t, w, []float64{1, 2, 4, 3})
+	// before the start of the window
+	w.AddAt(5, start.Add(-30*time.Second))
+	checkWindow(t, w, []float64{1, 2, 4, 3})
+
+	clock.now = start.Add(70 * time.Second)
+	checkWindow(t, w, []float64{2, 4, 3})
+	clock.now = start.Add(95 * time.Second)
+	checkWindow(t, w, []float64{3})
+	clock.now = start.Add(time.Hour)
+	checkWindow(t, w, []float64{})
+	if _, err := w.Mean(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+
+	// a long stream
+	rng := rand.New(rand.NewSource(2))
+	data := []time.Time{}
+	values := []float64{}
+	for i := 0; i < 2000; i++ {
+		clock.now = clock.now.Add(time.Duration(rng.Intn(2000)) * time.Millisecond)
+		x := rng.ExpFloat64()
+		w.Add(x)
+		data = append(data, clock.now)
+		values = append(values, x)
+		for data[0].Before(clock.now.Add(-time.Minute)) {
+			data, values = data[1:], values[1:]
+		}
+		checkWindow(t, w, values)
+	}
+}
+
+func TestWindowConcurrent(t *testing.T) {
+	t.Parallel()
+	w, err := NewWindow(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				w.Add(5)
+				w.Add(5)
+				if _, err := w.MeanConfidenceIntervals(0.95); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if w.N() != 100 {
+		t.Errorf("wrong N: want 100, got %d", w.N())
+	}
+	if mean, _ := w.Mean(); mean != 5 {
+		t.Errorf("wrong mean: want 5, got %f", mean)
+	}
+}
+
+func TestWindowErrors(t *testing.T) {
+	t.Parallel()
+	if _, err := NewWindow(0); !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("want %q, got %v", ErrInvalidWindow, err)
+	}
+	if _, err := NewTimeWindow(-time.Second); !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("want %q, got %v", ErrInvalidWindow, err)
+	}
+
+	w, err := NewWindow(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Add(1)
+	if _, err := w.StandardDeviation(); !errors.Is(err, ErrSampleTooSmall) {
+		t.Errorf("want %q, got %v", ErrSampleTooSmall, err)
+	}
+	w.Add(2)
+	if _, err := w.MeanConfidenceIntervals(1); !errors.Is(err, ErrInvalidConfidence) {
+		t.Errorf("want %q, got %v", ErrInvalidConfidence, err)
+	}
+}
+
+func TestMomentsMerge(t *testing.T) {
+	t.Parallel()
+	data := []float64{2, 3, 5, 6, 9, 1, 4}
+	var a, b moments
+	for _, x := range data[:3] {
+		a.add(x)
+	}
+	for _, x := range data[3:] {
+		b.add(x)
+	}
+	a.merge(b)
+	a.merge(moments{})
+
+	mean, _ := Mean(data)
+	sd, _ := StandardDeviation(data)
+	if a.n != len(data) || !equals(a.mean, mean, 1e-12) || !equals(math.Sqrt(a.variance()), sd, 1e-12) {
+		t.Errorf("want %d, %f, %f; got %d, %f, %f",
+			len(data), mean, sd, a.n, a.mean, math.Sqrt(a.variance()))
+	}
+}